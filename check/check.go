@@ -16,6 +16,13 @@ type Check interface {
 	Watch(ctx context.Context, channel chan Report)
 }
 
+// Triggerable is an optional interface implemented by checks that can be
+// evaluated on demand, outside of their normal schedule.
+type Triggerable interface {
+	Check
+	Trigger(ctx context.Context) Result
+}
+
 // Metadata contains information common to every check.
 type Metadata struct {
 	Name    string `json:"name"`
@@ -30,8 +37,38 @@ type Result struct {
 	CurrentHP float32     `json:"currentHP,omitempty"`
 	Error     error       `json:"error,omitempty"`
 	Timestamp time.Time   `json:"timestamp"`
+
+	// Details contains additional, check specific information about the
+	// evaluation (e.g. measured values).
+	Details map[string]interface{} `json:"details,omitempty"`
 }
 
+// UnmarshalJSON enables JSON deserialization of a result. Errors are restored
+// as an *Error.
+func (r *Result) UnmarshalJSON(bytes []byte) error {
+	type plain Result
+
+	aux := struct {
+		*plain
+		Error *Error `json:"error,omitempty"`
+	}{
+		plain: (*plain)(r),
+	}
+
+	if err := json.Unmarshal(bytes, &aux); err != nil {
+		return err
+	}
+
+	r.Error = nil
+	if aux.Error != nil {
+		r.Error = aux.Error
+	}
+
+	return nil
+}
+
+var _ json.Unmarshaler = &Result{}
+
 // WrapError will wrap the supplied err (if present) with a JSON serializable wrapper.
 func WrapError(err error) *Error {
 	if err == nil {
@@ -49,6 +86,9 @@ var _ error = &Error{}
 
 // Unwrap provides access to the underlying error object.
 func (e *Error) Unwrap() error {
+	if e == nil {
+		return nil
+	}
 	return e.error
 }
 
@@ -39,3 +39,15 @@ func TestResult(t *testing.T) {
 		require.Equal(t, errorResult, string(data))
 	}
 }
+
+func TestResult_Unmarshal(t *testing.T) {
+	result := check.Result{}
+	require.NoError(t, json.Unmarshal([]byte(errorResult), &result))
+	require.Equal(t, state.Outage, result.State)
+	require.Equal(t, check.ErrTimeout.Error(), result.Error.Error())
+
+	result = check.Result{}
+	require.NoError(t, json.Unmarshal([]byte(simpleResult), &result))
+	require.Equal(t, state.OK, result.State)
+	require.Nil(t, result.Error)
+}
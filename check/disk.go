@@ -0,0 +1,104 @@
+package check
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+
+	"github.com/mjpitz/go-gracefully/state"
+)
+
+// Disk inspects the filesystem containing Path. The percentage of free space
+// and free inodes are mapped onto a state using their respective thresholds
+// (values between 0 and 100). When Writable is set, a probe file is created
+// and removed to verify the path can be written to. Disk is currently
+// supported on Linux and macOS. Other platforms report an Unknown state.
+type Disk struct {
+	Path                string     `json:"path"`
+	FreeSpaceThresholds Thresholds `json:"freeSpaceThresholds"`
+	FreeInodeThresholds Thresholds `json:"freeInodeThresholds"`
+	Writable            bool       `json:"writable,omitempty"`
+}
+
+// fsStats contains the subset of filesystem statistics used by the Disk check.
+type fsStats struct {
+	totalBytes  uint64
+	freeBytes   uint64
+	totalInodes uint64
+	freeInodes  uint64
+}
+
+// Run performs a one time evaluation of the filesystem.
+func (d *Disk) Run(ctx context.Context) Result {
+	details := map[string]interface{}{
+		"path": d.Path,
+	}
+
+	stats, err := statfs(d.Path)
+	if err != nil {
+		return Result{
+			State:   state.Unknown,
+			Error:   WrapError(err),
+			Details: details,
+		}
+	}
+
+	details["totalBytes"] = stats.totalBytes
+	details["freeBytes"] = stats.freeBytes
+	details["totalInodes"] = stats.totalInodes
+	details["freeInodes"] = stats.freeInodes
+
+	states := make([]state.State, 0, 2)
+
+	if stats.totalBytes > 0 {
+		freeSpace := 100 * float64(stats.freeBytes) / float64(stats.totalBytes)
+		details["freeSpacePercent"] = freeSpace
+		states = append(states, d.FreeSpaceThresholds.Below(freeSpace))
+	}
+
+	// some filesystems do not report inodes
+	if stats.totalInodes > 0 {
+		freeInodes := 100 * float64(stats.freeInodes) / float64(stats.totalInodes)
+		details["freeInodesPercent"] = freeInodes
+		states = append(states, d.FreeInodeThresholds.Below(freeInodes))
+	}
+
+	if d.Writable {
+		if err := probe(d.Path); err != nil {
+			details["writable"] = false
+
+			return Result{
+				State:   state.Outage,
+				Error:   WrapError(err),
+				Details: details,
+			}
+		}
+
+		details["writable"] = true
+	}
+
+	return Result{
+		State:   state.Worst(states...),
+		Details: details,
+	}
+}
+
+// probe verifies that the directory is writable by creating and removing a file.
+func probe(dir string) error {
+	file, err := ioutil.TempFile(dir, ".gracefully-probe-")
+	if err != nil {
+		return err
+	}
+
+	_, writeErr := file.Write([]byte("ok"))
+	closeErr := file.Close()
+	removeErr := os.Remove(file.Name())
+
+	switch {
+	case writeErr != nil:
+		return writeErr
+	case closeErr != nil:
+		return closeErr
+	}
+	return removeErr
+}
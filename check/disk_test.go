@@ -0,0 +1,61 @@
+package check_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/state"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisk(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("statfs is not supported on " + runtime.GOOS)
+	}
+
+	dir, err := ioutil.TempDir("", "gracefully")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	{
+		result := (&check.Disk{Path: dir, Writable: true}).Run(context.TODO())
+		require.Equal(t, state.OK, result.State)
+		require.Equal(t, true, result.Details["writable"])
+		require.True(t, result.Details["totalBytes"].(uint64) > 0)
+
+		// the probe file should be cleaned up
+		files, err := ioutil.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, files, 0)
+	}
+
+	{
+		result := (&check.Disk{
+			Path:                dir,
+			FreeSpaceThresholds: check.Thresholds{Major: 100},
+		}).Run(context.TODO())
+		require.Equal(t, state.Major, result.State)
+	}
+
+	{
+		result := (&check.Disk{Path: filepath.Join(dir, "missing")}).Run(context.TODO())
+		require.Equal(t, state.Unknown, result.State)
+		require.NotNil(t, result.Error)
+	}
+
+	// root can write regardless of permissions
+	if os.Geteuid() != 0 {
+		require.NoError(t, os.Chmod(dir, 0500))
+		defer os.Chmod(dir, 0700)
+
+		result := (&check.Disk{Path: dir, Writable: true}).Run(context.TODO())
+		require.Equal(t, state.Outage, result.State)
+		require.Equal(t, false, result.Details["writable"])
+	}
+}
@@ -0,0 +1,166 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/mjpitz/go-gracefully/state"
+)
+
+// DNS resolves a Host and validates the answers. RecordType selects the type
+// of record to look up (A, AAAA, CNAME, MX, NS or TXT). When unset, all of the
+// addresses for the host are resolved. Queries are sent to the Resolver
+// address ("host:port") when provided, and to the system resolver otherwise.
+//
+// A non-existent domain (NXDOMAIN) is an Outage, while a failing or timed out
+// server (e.g. SERVFAIL) is Major. When the answers do not contain all of the
+// Expected values or contain fewer than MinAnswers records, the check is an
+// Outage. Otherwise, the state is derived from the resolution latency.
+type DNS struct {
+	Host              string             `json:"host"`
+	Resolver          string             `json:"resolver,omitempty"`
+	RecordType        string             `json:"recordType,omitempty"`
+	Expected          []string           `json:"expected,omitempty"`
+	MinAnswers        int                `json:"minAnswers,omitempty"`
+	LatencyThresholds DurationThresholds `json:"latencyThresholds"`
+}
+
+// Run performs a one time evaluation of the host.
+func (d *DNS) Run(ctx context.Context) Result {
+	details := map[string]interface{}{
+		"host": d.Host,
+	}
+
+	start := time.Now()
+	answers, err := d.lookup(ctx, d.resolver())
+	latency := time.Since(start)
+
+	details["latency"] = latency.String()
+
+	if err != nil {
+		resultState := state.Outage
+		if dnsErr, ok := err.(*net.DNSError); ok {
+			switch {
+			case dnsErr.IsNotFound:
+				details["rcode"] = "NXDOMAIN"
+			case dnsErr.IsTimeout:
+				resultState = state.Major
+			case dnsErr.IsTemporary:
+				details["rcode"] = "SERVFAIL"
+				resultState = state.Major
+			}
+		}
+
+		return Result{
+			State:   resultState,
+			Error:   WrapError(err),
+			Details: details,
+		}
+	}
+
+	details["answers"] = answers
+
+	if len(answers) < d.MinAnswers {
+		return Result{
+			State:   state.Outage,
+			Error:   WrapError(fmt.Errorf("expected at least %d answers, got %d", d.MinAnswers, len(answers))),
+			Details: details,
+		}
+	}
+
+	present := make(map[string]bool, len(answers))
+	for _, answer := range answers {
+		present[normalizeAnswer(answer)] = true
+	}
+
+	for _, expected := range d.Expected {
+		if !present[normalizeAnswer(expected)] {
+			return Result{
+				State:   state.Outage,
+				Error:   WrapError(fmt.Errorf("expected answer %q not found", expected)),
+				Details: details,
+			}
+		}
+	}
+
+	return Result{
+		State:   d.LatencyThresholds.Above(latency),
+		Details: details,
+	}
+}
+
+func (d *DNS) resolver() *net.Resolver {
+	if d.Resolver == "" {
+		return net.DefaultResolver
+	}
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			dialer := &net.Dialer{}
+			return dialer.DialContext(ctx, network, d.Resolver)
+		},
+	}
+}
+
+func (d *DNS) lookup(ctx context.Context, resolver *net.Resolver) ([]string, error) {
+	switch strings.ToUpper(d.RecordType) {
+	case "":
+		return resolver.LookupHost(ctx, d.Host)
+	case "A", "AAAA":
+		addrs, err := resolver.LookupIPAddr(ctx, d.Host)
+		if err != nil {
+			return nil, err
+		}
+
+		wantV4 := strings.ToUpper(d.RecordType) == "A"
+		answers := make([]string, 0, len(addrs))
+		for _, addr := range addrs {
+			if (addr.IP.To4() != nil) == wantV4 {
+				answers = append(answers, addr.IP.String())
+			}
+		}
+		return answers, nil
+	case "CNAME":
+		cname, err := resolver.LookupCNAME(ctx, d.Host)
+		if err != nil {
+			return nil, err
+		}
+		return []string{cname}, nil
+	case "MX":
+		records, err := resolver.LookupMX(ctx, d.Host)
+		if err != nil {
+			return nil, err
+		}
+
+		answers := make([]string, 0, len(records))
+		for _, record := range records {
+			answers = append(answers, record.Host)
+		}
+		return answers, nil
+	case "NS":
+		records, err := resolver.LookupNS(ctx, d.Host)
+		if err != nil {
+			return nil, err
+		}
+
+		answers := make([]string, 0, len(records))
+		for _, record := range records {
+			answers = append(answers, record.Host)
+		}
+		return answers, nil
+	case "TXT":
+		return resolver.LookupTXT(ctx, d.Host)
+	default:
+		return nil, fmt.Errorf("unsupported record type %q", d.RecordType)
+	}
+}
+
+// normalizeAnswer allows expected host names to be provided with or without
+// their trailing dot.
+func normalizeAnswer(answer string) string {
+	return strings.ToLower(strings.TrimSuffix(answer, "."))
+}
@@ -0,0 +1,124 @@
+package check_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/state"
+
+	"github.com/stretchr/testify/require"
+)
+
+// startDNSStub starts a UDP DNS server that answers A queries for
+// "example.test." and fails all other names.
+func startDNSStub(t *testing.T) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			request := dnsmessage.Message{}
+			if err := request.Unpack(buf[:n]); err != nil || len(request.Questions) == 0 {
+				continue
+			}
+
+			question := request.Questions[0]
+			response := dnsmessage.Message{
+				Header: dnsmessage.Header{
+					ID:            request.ID,
+					Response:      true,
+					Authoritative: true,
+				},
+				Questions: request.Questions,
+			}
+
+			switch question.Name.String() {
+			case "example.test.":
+				if question.Type == dnsmessage.TypeA {
+					for _, ip := range [][4]byte{{10, 0, 0, 1}, {10, 0, 0, 2}} {
+						response.Answers = append(response.Answers, dnsmessage.Resource{
+							Header: dnsmessage.ResourceHeader{
+								Name:  question.Name,
+								Type:  dnsmessage.TypeA,
+								Class: dnsmessage.ClassINET,
+								TTL:   60,
+							},
+							Body: &dnsmessage.AResource{A: ip},
+						})
+					}
+				}
+			case "broken.test.":
+				response.RCode = dnsmessage.RCodeServerFailure
+			default:
+				response.RCode = dnsmessage.RCodeNameError
+			}
+
+			packed, err := response.Pack()
+			if err != nil {
+				continue
+			}
+
+			_, _ = conn.WriteTo(packed, addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func TestDNS(t *testing.T) {
+	resolver := startDNSStub(t)
+
+	{
+		result := (&check.DNS{
+			Host:       "example.test.",
+			Resolver:   resolver,
+			RecordType: "A",
+			Expected:   []string{"10.0.0.2"},
+			MinAnswers: 2,
+		}).Run(context.TODO())
+		require.Equal(t, state.OK, result.State)
+		require.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, result.Details["answers"])
+	}
+
+	{
+		result := (&check.DNS{
+			Host:     "example.test.",
+			Resolver: resolver,
+			Expected: []string{"10.0.0.3"},
+		}).Run(context.TODO())
+		require.Equal(t, state.Outage, result.State)
+	}
+
+	{
+		result := (&check.DNS{
+			Host:       "example.test.",
+			Resolver:   resolver,
+			MinAnswers: 3,
+		}).Run(context.TODO())
+		require.Equal(t, state.Outage, result.State)
+	}
+
+	{
+		result := (&check.DNS{Host: "missing.test.", Resolver: resolver}).Run(context.TODO())
+		require.Equal(t, state.Outage, result.State)
+		require.Equal(t, "NXDOMAIN", result.Details["rcode"])
+	}
+
+	{
+		result := (&check.DNS{Host: "broken.test.", Resolver: resolver}).Run(context.TODO())
+		require.Equal(t, state.Major, result.State)
+		require.Equal(t, "SERVFAIL", result.Details["rcode"])
+	}
+}
@@ -1,8 +1,53 @@
 package check
 
-import "fmt"
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/mjpitz/go-gracefully/state"
+)
 
 var (
 	// ErrTimeout is returned when a check times out during evaluation
 	ErrTimeout = fmt.Errorf("timed out waiting for check")
+
+	// ErrStale is returned when a stream has not produced a result within its max silence
+	ErrStale = fmt.Errorf("no results received from stream")
+
+	// ErrUnsupported is returned when a check cannot be evaluated on the current platform
+	ErrUnsupported = fmt.Errorf("check not supported on this platform")
 )
+
+// PanicError is returned when a check panics during evaluation. It captures
+// the recovered value along with the stack trace of the panicking go routine.
+// The stack trace is left out of the message, which is served by the health
+// endpoints.
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+var _ error = &PanicError{}
+
+// panicResult converts a recovered value into a Result. It must be called from
+// the deferred function that recovered so that the stack trace is accurate.
+func panicResult(value interface{}, panicState state.State, clock clockwork.Clock) Result {
+	if panicState == "" {
+		panicState = state.Outage
+	}
+
+	return Result{
+		State: panicState,
+		Error: WrapError(&PanicError{
+			Value: value,
+			Stack: debug.Stack(),
+		}),
+		Timestamp: clock.Now(),
+	}
+}
@@ -0,0 +1,254 @@
+package check
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/mjpitz/go-gracefully/state"
+)
+
+// DefaultMaxOutput is the number of bytes of stdout and stderr captured by an
+// Exec check when no MaxOutput is configured.
+const DefaultMaxOutput = 4096
+
+// Exec runs a command that follows the Nagios plugin conventions. The exit
+// code of the command determines the state: 0 is OK, 1 is WarningState (Minor
+// when unset), 2 is Outage, and 3 (or any other code) is Unknown. Env is
+// appended to the environment of the current process. The first line of
+// output is used as the error message when the command does not succeed.
+// Output is truncated to MaxOutput bytes and recorded in the details along
+// with any performance data ("label=value[UOM];[warn];[crit];[min];[max]")
+// following a "|". The command, along with any processes it started, is
+// killed once the context is done.
+type Exec struct {
+	Command      string      `json:"command"`
+	Args         []string    `json:"args,omitempty"`
+	Env          []string    `json:"env,omitempty"`
+	Dir          string      `json:"dir,omitempty"`
+	WarningState state.State `json:"warningState,omitempty"`
+	MaxOutput    int         `json:"maxOutput,omitempty"`
+}
+
+// Run performs a one time evaluation of the command.
+func (e *Exec) Run(ctx context.Context) Result {
+	maxOutput := e.MaxOutput
+	if maxOutput <= 0 {
+		maxOutput = DefaultMaxOutput
+	}
+
+	stdout := &limitedBuffer{limit: maxOutput}
+	stderr := &limitedBuffer{limit: maxOutput}
+
+	cmd := exec.Command(e.Command, e.Args...)
+	cmd.Dir = e.Dir
+	cmd.Env = append(os.Environ(), e.Env...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	setProcessGroup(cmd)
+
+	err := cmd.Start()
+	if err == nil {
+		// children of the command hold on to its output until they exit, so
+		// the whole group is killed
+		done := make(chan struct{})
+		go func() {
+			select {
+			case <-ctx.Done():
+				_ = killProcessGroup(cmd)
+			case <-done:
+			}
+		}()
+
+		err = cmd.Wait()
+		close(done)
+	}
+
+	details := map[string]interface{}{
+		"stdout": stdout.String(),
+		"stderr": stderr.String(),
+	}
+
+	if stdout.truncated || stderr.truncated {
+		details["truncated"] = true
+	}
+
+	exitCode := 0
+	if err != nil {
+		exitErr := &exec.ExitError{}
+		if !errors.As(err, &exitErr) {
+			// the command could not be started
+			return Result{
+				State:   state.Unknown,
+				Error:   WrapError(err),
+				Details: details,
+			}
+		}
+
+		exitCode = exitErr.ExitCode()
+	}
+
+	details["exitCode"] = exitCode
+
+	text, perfData := parseOutput(stdout.String())
+	if len(perfData) > 0 {
+		details["perfdata"] = perfData
+	}
+
+	resultState := state.Unknown
+	switch exitCode {
+	case 0:
+		return Result{
+			State:   state.OK,
+			Details: details,
+		}
+	case 1:
+		resultState = e.WarningState
+		if resultState == "" {
+			resultState = state.Minor
+		}
+	case 2:
+		resultState = state.Outage
+	}
+
+	if text == "" {
+		text = fmt.Sprintf("%s exited with code %d", e.Command, exitCode)
+	}
+
+	return Result{
+		State:   resultState,
+		Error:   WrapError(errors.New(text)),
+		Details: details,
+	}
+}
+
+// parseOutput splits plugin output into the status text (the first line, up
+// to the "|") and the performance data found on any line.
+func parseOutput(output string) (string, map[string]interface{}) {
+	perfData := make(map[string]interface{})
+	text := ""
+
+	for i, line := range strings.Split(output, "\n") {
+		parts := strings.SplitN(line, "|", 2)
+		if i == 0 {
+			text = strings.TrimSpace(parts[0])
+		}
+
+		if len(parts) == 2 {
+			for label, value := range parsePerfData(parts[1]) {
+				perfData[label] = value
+			}
+		}
+	}
+
+	return text, perfData
+}
+
+// parsePerfData parses space separated performance data. Labels containing
+// spaces are enclosed in single quotes.
+func parsePerfData(data string) map[string]interface{} {
+	perfData := make(map[string]interface{})
+
+	for data = strings.TrimSpace(data); data != ""; data = strings.TrimSpace(data) {
+		label := ""
+
+		if data[0] == '\'' {
+			end := strings.Index(data[1:], "'=")
+			if end < 0 {
+				break
+			}
+
+			label = data[1 : end+1]
+			data = data[end+3:]
+		} else {
+			end := strings.Index(data, "=")
+			if end < 0 {
+				break
+			}
+
+			label = data[:end]
+			data = data[end+1:]
+		}
+
+		end := strings.IndexAny(data, " \t")
+		if end < 0 {
+			end = len(data)
+		}
+
+		if metric := parseMetric(data[:end]); metric != nil {
+			perfData[label] = metric
+		}
+		data = data[end:]
+	}
+
+	return perfData
+}
+
+// parseMetric parses "value[UOM];[warn];[crit];[min];[max]".
+func parseMetric(raw string) map[string]interface{} {
+	fields := strings.Split(raw, ";")
+
+	value := strings.TrimRightFunc(fields[0], func(r rune) bool {
+		return !strings.ContainsRune("0123456789.-", r)
+	})
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil
+	}
+
+	metric := map[string]interface{}{
+		"value": parsed,
+	}
+
+	if uom := fields[0][len(value):]; uom != "" {
+		metric["uom"] = uom
+	}
+
+	for i, key := range []string{"warn", "crit", "min", "max"} {
+		if i+1 >= len(fields) || fields[i+1] == "" {
+			continue
+		}
+
+		if key == "min" || key == "max" {
+			if number, err := strconv.ParseFloat(fields[i+1], 64); err == nil {
+				metric[key] = number
+			}
+			continue
+		}
+
+		// thresholds are ranges (e.g. "10:20") and are kept as is
+		metric[key] = fields[i+1]
+	}
+
+	return metric
+}
+
+// limitedBuffer retains up to limit bytes, discarding the remainder.
+type limitedBuffer struct {
+	buffer    bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	remaining := b.limit - b.buffer.Len()
+	if remaining < len(p) {
+		b.truncated = true
+		if remaining > 0 {
+			b.buffer.Write(p[:remaining])
+		}
+		return len(p), nil
+	}
+
+	return b.buffer.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buffer.String()
+}
@@ -0,0 +1,91 @@
+package check_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/state"
+
+	"github.com/stretchr/testify/require"
+)
+
+func sh(script string) *check.Exec {
+	return &check.Exec{
+		Command: "sh",
+		Args:    []string{"-c", script},
+	}
+}
+
+func TestExec(t *testing.T) {
+	tests := []struct {
+		exec     *check.Exec
+		expected state.State
+	}{
+		{exec: sh("echo OK; exit 0"), expected: state.OK},
+		{exec: sh("echo WARNING; exit 1"), expected: state.Minor},
+		{exec: &check.Exec{Command: "sh", Args: []string{"-c", "exit 1"}, WarningState: state.Major}, expected: state.Major},
+		{exec: sh("echo CRITICAL; exit 2"), expected: state.Outage},
+		{exec: sh("echo UNKNOWN; exit 3"), expected: state.Unknown},
+		{exec: sh("exit 42"), expected: state.Unknown},
+		{exec: &check.Exec{Command: "/does/not/exist"}, expected: state.Unknown},
+	}
+
+	for _, test := range tests {
+		result := test.exec.Run(context.TODO())
+		require.Equal(t, test.expected, result.State, test.exec.Args)
+	}
+}
+
+func TestExec_Output(t *testing.T) {
+	result := (&check.Exec{
+		Command: "sh",
+		Args: []string{"-c", `echo "DISK CRITICAL - free space: / 3% | '/ free'=3MB;10:;5:;0;100 inodes=98%"; ` +
+			`echo "more detail | load=1.5;2;4"; echo "$GREETING" >&2; pwd >&2; exit 2`},
+		Env: []string{"GREETING=hello"},
+		Dir: "/",
+	}).Run(context.TODO())
+
+	require.Equal(t, state.Outage, result.State)
+	require.Equal(t, "DISK CRITICAL - free space: / 3%", result.Error.Error())
+	require.Equal(t, 2, result.Details["exitCode"])
+	require.Equal(t, "hello\n/\n", result.Details["stderr"])
+
+	perfData := result.Details["perfdata"].(map[string]interface{})
+	require.Equal(t, map[string]interface{}{
+		"value": float64(3),
+		"uom":   "MB",
+		"warn":  "10:",
+		"crit":  "5:",
+		"min":   float64(0),
+		"max":   float64(100),
+	}, perfData["/ free"])
+	require.Equal(t, map[string]interface{}{"value": float64(98), "uom": "%"}, perfData["inodes"])
+	require.Equal(t, map[string]interface{}{"value": 1.5, "warn": "2", "crit": "4"}, perfData["load"])
+}
+
+func TestExec_Truncated(t *testing.T) {
+	result := (&check.Exec{
+		Command:   "sh",
+		Args:      []string{"-c", "echo 0123456789"},
+		MaxOutput: 4,
+	}).Run(context.TODO())
+
+	require.Equal(t, state.OK, result.State)
+	require.Equal(t, "0123", result.Details["stdout"])
+	require.Equal(t, true, result.Details["truncated"])
+}
+
+func TestExec_Timeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.TODO(), 200*time.Millisecond)
+	defer cancel()
+
+	// the sleep is a child of the shell, and holds on to its output
+	start := time.Now()
+	result := sh("sleep 3; echo done").Run(ctx)
+
+	require.Less(t, int64(time.Since(start)), int64(2*time.Second))
+	require.Equal(t, state.Unknown, result.State)
+	require.NotContains(t, result.Details["stdout"], "done")
+}
@@ -0,0 +1,32 @@
+//go:build linux
+// +build linux
+
+package check
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileDescriptors returns the number of open file descriptors and the soft
+// limit for the current process.
+func fileDescriptors() (int, uint64, error) {
+	dir, err := os.Open("/proc/self/fd")
+	if err != nil {
+		return 0, 0, err
+	}
+	defer dir.Close()
+
+	names, err := dir.Readdirnames(-1)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	limit := &syscall.Rlimit{}
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, limit); err != nil {
+		return 0, 0, err
+	}
+
+	// exclude the descriptor used to read the directory
+	return len(names) - 1, limit.Cur, nil
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+// +build !linux
+
+package check
+
+func fileDescriptors() (int, uint64, error) {
+	return 0, 0, ErrUnsupported
+}
@@ -0,0 +1,100 @@
+package check
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/mjpitz/go-gracefully/state"
+)
+
+// UnixLayout can be used as the Layout of a File check to parse timestamps
+// written as the number of seconds since the Unix epoch.
+const UnixLayout = "unix"
+
+// File verifies that a heartbeat file is being kept up to date. By default,
+// the modification time of the file is used. When a Layout is provided, the
+// timestamp is instead parsed from the contents of the file using either a
+// time layout (e.g. time.RFC3339) or UnixLayout. The age of the timestamp is
+// mapped onto a state using the AgeThresholds. A missing file is an Outage.
+type File struct {
+	Path          string             `json:"path"`
+	Layout        string             `json:"layout,omitempty"`
+	AgeThresholds DurationThresholds `json:"ageThresholds"`
+	Clock         clockwork.Clock    `json:"-"`
+}
+
+// Run performs a one time evaluation of the file.
+func (f *File) Run(ctx context.Context) Result {
+	if f.Clock == nil {
+		f.Clock = clockwork.NewRealClock()
+	}
+
+	details := map[string]interface{}{
+		"path": f.Path,
+	}
+
+	timestamp, err := f.timestamp()
+	if err != nil {
+		resultState := state.Unknown
+		if os.IsNotExist(err) {
+			resultState = state.Outage
+		}
+
+		return Result{
+			State:   resultState,
+			Error:   WrapError(err),
+			Details: details,
+		}
+	}
+
+	age := f.Clock.Now().Sub(timestamp)
+
+	details["timestamp"] = timestamp.UTC().Format(time.RFC3339)
+	details["age"] = age.String()
+
+	return Result{
+		State:   f.AgeThresholds.Above(age),
+		Details: details,
+	}
+}
+
+func (f *File) timestamp() (time.Time, error) {
+	if f.Layout == "" {
+		info, err := os.Stat(f.Path)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return info.ModTime(), nil
+	}
+
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer file.Close()
+
+	// timestamps are short, avoid reading large files into memory
+	contents, err := ioutil.ReadAll(io.LimitReader(file, 1024))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	value := strings.TrimSpace(string(contents))
+
+	if f.Layout == UnixLayout {
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(seconds, 0), nil
+	}
+
+	return time.Parse(f.Layout, value)
+}
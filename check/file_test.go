@@ -0,0 +1,82 @@
+package check_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/state"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gracefully")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	lastSuccess := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	thresholds := check.DurationThresholds{
+		Minor:  time.Hour,
+		Major:  2 * time.Hour,
+		Outage: 4 * time.Hour,
+	}
+
+	heartbeat := filepath.Join(dir, "heartbeat")
+	require.NoError(t, ioutil.WriteFile(heartbeat, nil, 0644))
+	require.NoError(t, os.Chtimes(heartbeat, lastSuccess, lastSuccess))
+
+	rfc3339 := filepath.Join(dir, "rfc3339")
+	require.NoError(t, ioutil.WriteFile(rfc3339, []byte(lastSuccess.Format(time.RFC3339)+"\n"), 0644))
+
+	unix := filepath.Join(dir, "unix")
+	require.NoError(t, ioutil.WriteFile(unix, []byte(strconv.FormatInt(lastSuccess.Unix(), 10)), 0644))
+
+	files := map[string]string{
+		heartbeat: "",
+		rfc3339:   time.RFC3339,
+		unix:      check.UnixLayout,
+	}
+
+	tests := []struct {
+		age      time.Duration
+		expected state.State
+	}{
+		{age: 30 * time.Minute, expected: state.OK},
+		{age: 90 * time.Minute, expected: state.Minor},
+		{age: 3 * time.Hour, expected: state.Major},
+		{age: 5 * time.Hour, expected: state.Outage},
+	}
+
+	for path, layout := range files {
+		for _, test := range tests {
+			result := (&check.File{
+				Path:          path,
+				Layout:        layout,
+				AgeThresholds: thresholds,
+				Clock:         clockwork.NewFakeClockAt(lastSuccess.Add(test.age)),
+			}).Run(context.TODO())
+
+			require.Equal(t, test.expected, result.State, path)
+			require.Equal(t, test.age.String(), result.Details["age"])
+		}
+	}
+
+	{
+		result := (&check.File{Path: filepath.Join(dir, "missing")}).Run(context.TODO())
+		require.Equal(t, state.Outage, result.State)
+	}
+
+	{
+		result := (&check.File{Path: heartbeat, Layout: time.RFC3339}).Run(context.TODO())
+		require.Equal(t, state.Unknown, result.State)
+		require.NotNil(t, result.Error)
+	}
+}
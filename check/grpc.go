@@ -0,0 +1,123 @@
+package check
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/mjpitz/go-gracefully/state"
+)
+
+// GRPCHealth evaluates a downstream service using the grpc.health.v1 protocol.
+// The serving status reported for the Service is mapped onto a state, and is
+// combined with the connectivity state of the client connection.
+type GRPCHealth struct {
+	Conn    *grpc.ClientConn `json:"-"`
+	Service string           `json:"service,omitempty"`
+}
+
+// Run performs a one time evaluation of the service using the Check method.
+func (g *GRPCHealth) Run(ctx context.Context) Result {
+	response, err := healthpb.NewHealthClient(g.Conn).Check(ctx, &healthpb.HealthCheckRequest{
+		Service: g.Service,
+	})
+
+	return g.result(response, err)
+}
+
+// Watch observes the service using the Watch method, reporting a result for
+// each change in serving status. It returns once the stream fails, so it's
+// best used as the WatchFunc of a Stream with a RestartPolicy.
+func (g *GRPCHealth) Watch(ctx context.Context, channel chan Result) {
+	stream, err := healthpb.NewHealthClient(g.Conn).Watch(ctx, &healthpb.HealthCheckRequest{
+		Service: g.Service,
+	})
+
+	for err == nil {
+		var response *healthpb.HealthCheckResponse
+		if response, err = stream.Recv(); err == nil {
+			select {
+			case channel <- g.result(response, nil):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	select {
+	case channel <- g.result(nil, err):
+	case <-ctx.Done():
+	}
+}
+
+func (g *GRPCHealth) result(response *healthpb.HealthCheckResponse, err error) Result {
+	connectivityState := g.Conn.GetState()
+
+	details := map[string]interface{}{
+		"service":      g.Service,
+		"connectivity": connectivityState.String(),
+	}
+
+	if err != nil {
+		resultState := state.Outage
+
+		switch status.Code(err) {
+		case codes.Unimplemented:
+			// the server does not implement the health protocol
+			resultState = state.Unknown
+		case codes.NotFound:
+			details["status"] = healthpb.HealthCheckResponse_SERVICE_UNKNOWN.String()
+		}
+
+		return Result{
+			State:   resultState,
+			Error:   WrapError(err),
+			Details: details,
+		}
+	}
+
+	details["status"] = response.GetStatus().String()
+
+	var servingErr error
+	servingState := state.OK
+
+	switch response.GetStatus() {
+	case healthpb.HealthCheckResponse_SERVING:
+	case healthpb.HealthCheckResponse_NOT_SERVING, healthpb.HealthCheckResponse_SERVICE_UNKNOWN:
+		servingState = state.Outage
+		servingErr = fmt.Errorf("service %q is %s", g.Service, response.GetStatus())
+	default:
+		servingState = state.Unknown
+	}
+
+	result := Result{
+		State:   state.Worst(servingState, forConnectivity(connectivityState)),
+		Details: details,
+	}
+
+	if servingErr != nil {
+		result.Error = WrapError(servingErr)
+	}
+
+	return result
+}
+
+// forConnectivity maps the state of a client connection onto a state.
+func forConnectivity(connectivityState connectivity.State) state.State {
+	switch connectivityState {
+	case connectivity.TransientFailure, connectivity.Shutdown:
+		return state.Outage
+	case connectivity.Connecting:
+		return state.Minor
+	}
+	return state.OK
+}
@@ -0,0 +1,87 @@
+package check_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/state"
+
+	"github.com/stretchr/testify/require"
+)
+
+func startHealthServer(t *testing.T) (*health.Server, *grpc.ClientConn) {
+	listener := bufconn.Listen(1024 * 1024)
+
+	healthServer := health.NewServer()
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return healthServer, conn
+}
+
+func TestGRPCHealth_Run(t *testing.T) {
+	healthServer, conn := startHealthServer(t)
+
+	healthServer.SetServingStatus("greeter", healthpb.HealthCheckResponse_SERVING)
+
+	{
+		result := (&check.GRPCHealth{Conn: conn, Service: "greeter"}).Run(context.TODO())
+		require.Equal(t, state.OK, result.State)
+		require.True(t, result.Error == nil)
+		require.Equal(t, "SERVING", result.Details["status"])
+		require.Equal(t, "READY", result.Details["connectivity"])
+	}
+
+	healthServer.SetServingStatus("greeter", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	{
+		result := (&check.GRPCHealth{Conn: conn, Service: "greeter"}).Run(context.TODO())
+		require.Equal(t, state.Outage, result.State)
+		require.Equal(t, "NOT_SERVING", result.Details["status"])
+	}
+
+	{
+		result := (&check.GRPCHealth{Conn: conn, Service: "missing"}).Run(context.TODO())
+		require.Equal(t, state.Outage, result.State)
+		require.Equal(t, "SERVICE_UNKNOWN", result.Details["status"])
+	}
+}
+
+func TestGRPCHealth_Watch(t *testing.T) {
+	healthServer, conn := startHealthServer(t)
+
+	healthServer.SetServingStatus("greeter", healthpb.HealthCheckResponse_SERVING)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	results := make(chan check.Result, 1)
+	go (&check.GRPCHealth{Conn: conn, Service: "greeter"}).Watch(ctx, results)
+
+	require.Equal(t, state.OK, (<-results).State)
+
+	healthServer.SetServingStatus("greeter", healthpb.HealthCheckResponse_NOT_SERVING)
+	require.Equal(t, state.Outage, (<-results).State)
+
+	healthServer.SetServingStatus("greeter", healthpb.HealthCheckResponse_SERVING)
+	require.Equal(t, state.OK, (<-results).State)
+}
@@ -0,0 +1,154 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/mjpitz/go-gracefully/state"
+)
+
+// Heartbeat is a Check implementation for in-process workers. Workers call
+// Beat to signal that they are alive, or Fail to report an Outage. As the time
+// since the last call exceeds each of the Thresholds, the check degrades
+// accordingly (e.g. Minor, then Major, then Outage). Results are only reported
+// when the state of the check changes.
+type Heartbeat struct {
+	Metadata
+	Thresholds DurationThresholds `json:"thresholds"`
+	Clock      clockwork.Clock    `json:"-"`
+
+	once   sync.Once
+	events chan Result
+}
+
+// GetMetadata returns meta information about the check.
+func (h *Heartbeat) GetMetadata() Metadata {
+	return h.Metadata
+}
+
+// Beat signals that the worker is alive and healthy.
+func (h *Heartbeat) Beat() {
+	h.record(Result{State: state.OK})
+}
+
+// Fail signals that the worker is alive, but failing. The check reports an
+// Outage until the next Beat.
+func (h *Heartbeat) Fail(err error) {
+	result := Result{State: state.Outage}
+	if err != nil {
+		result.Error = WrapError(err)
+	}
+	h.record(result)
+}
+
+// record stores the latest event, replacing any that have not been observed.
+func (h *Heartbeat) record(result Result) {
+	h.init()
+
+	for {
+		select {
+		case h.events <- result:
+			return
+		default:
+		}
+
+		select {
+		case <-h.events:
+		default:
+		}
+	}
+}
+
+// Watch observes heartbeats and the time that passes between them.
+func (h *Heartbeat) Watch(ctx context.Context, channel chan Report) {
+	h.init()
+
+	go func() {
+		stopCh := ctx.Done()
+
+		last := h.Clock.Now()
+		lastEvent := Result{State: state.OK}
+		current := state.Unknown
+		timer := h.next(0)
+
+		emit := func(result Result) {
+			current = result.State
+			result.Timestamp = h.Clock.Now()
+			result.Details = map[string]interface{}{
+				"lastBeat": last.UTC().Format(time.RFC3339Nano),
+			}
+
+			channel <- Report{
+				Check:  h,
+				Result: result,
+			}
+		}
+
+		for {
+			select {
+			case event := <-h.events:
+				last = h.Clock.Now()
+				lastEvent = event
+
+				if event.State != current || event.Error != nil {
+					emit(event)
+				}
+
+				// a pending timer will re-arm itself relative to the new beat
+				if timer == nil {
+					timer = h.next(0)
+				}
+			case <-timer:
+				elapsed := h.Clock.Now().Sub(last)
+				timer = h.next(elapsed)
+
+				silentState := h.Thresholds.Above(elapsed)
+				if worst := state.Worst(lastEvent.State, silentState); worst != current {
+					result := lastEvent
+					if silentState == worst {
+						result = Result{
+							State: worst,
+							Error: WrapError(fmt.Errorf("no heartbeat for %s", elapsed)),
+						}
+					}
+					emit(result)
+				}
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// next returns a timer that fires at the next threshold beyond the elapsed
+// duration, or nil when all thresholds have been exceeded.
+func (h *Heartbeat) next(elapsed time.Duration) <-chan time.Time {
+	next := time.Duration(0)
+	for _, threshold := range []time.Duration{h.Thresholds.Minor, h.Thresholds.Major, h.Thresholds.Outage} {
+		if threshold > elapsed && (next == 0 || threshold < next) {
+			next = threshold
+		}
+	}
+
+	if next == 0 {
+		return nil
+	}
+
+	return h.Clock.After(next - elapsed)
+}
+
+func (h *Heartbeat) init() {
+	h.once.Do(func() {
+		if h.Clock == nil {
+			h.Clock = clockwork.NewRealClock()
+		}
+
+		h.events = make(chan Result, 1)
+	})
+}
+
+var _ Check = &Heartbeat{}
@@ -0,0 +1,75 @@
+package check_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/state"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeartbeat(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+
+	heartbeat := &check.Heartbeat{
+		Thresholds: check.DurationThresholds{
+			Minor:  time.Second,
+			Major:  time.Second * 2,
+			Outage: time.Second * 3,
+		},
+		Clock: clock,
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	reportChan := make(chan check.Report, 1)
+	heartbeat.Watch(ctx, reportChan)
+
+	heartbeat.Beat()
+	{
+		report := <-reportChan
+		require.Equal(t, state.OK, report.Result.State)
+		require.Nil(t, report.Result.Error)
+	}
+
+	for _, expected := range []state.State{state.Minor, state.Major, state.Outage} {
+		clock.BlockUntil(1)
+		clock.Advance(time.Second)
+
+		report := <-reportChan
+		require.Equal(t, expected, report.Result.State)
+		require.NotNil(t, report.Result.Error)
+	}
+
+	heartbeat.Beat()
+	{
+		report := <-reportChan
+		require.Equal(t, state.OK, report.Result.State)
+		require.Nil(t, report.Result.Error)
+	}
+
+	heartbeat.Fail(fmt.Errorf("failed to process"))
+	{
+		report := <-reportChan
+		require.Equal(t, state.Outage, report.Result.State)
+		require.Equal(t, "failed to process", report.Result.Error.Error())
+	}
+
+	// the failure is not replaced by silence
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+
+	heartbeat.Beat()
+	{
+		report := <-reportChan
+		require.Equal(t, state.OK, report.Result.State)
+		require.Nil(t, report.Result.Error)
+	}
+}
@@ -0,0 +1,254 @@
+package check
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/mjpitz/go-gracefully/state"
+)
+
+// DefaultMaxSamples is the number of outcomes retained by a Passive check when
+// no MaxSamples is configured.
+const DefaultMaxSamples = 1000
+
+// DefaultPercentile is the latency percentile evaluated by a Passive check when
+// no Percentile is configured.
+const DefaultPercentile = 0.99
+
+// Passive derives its state from the outcomes of real operations, recorded
+// using Record. Outcomes are retained for the last Window of time and up to
+// MaxSamples operations, in a buffer that is allocated by the first Record and
+// not resized afterwards. The ratio of failed operations is mapped onto a state
+// using the ErrorThresholds (e.g. 0.05 for 5%), and the latency at the given
+// Percentile (between 0 and 1) using the LatencyThresholds. The check remains
+// OK until at least MinSamples operations have been recorded. RoundTripper,
+// UnaryClientInterceptor, and StreamClientInterceptor record outcomes from
+// HTTP and gRPC clients automatically.
+type Passive struct {
+	Window            time.Duration      `json:"window,string,omitempty"`
+	MaxSamples        int                `json:"maxSamples,omitempty"`
+	MinSamples        int                `json:"minSamples,omitempty"`
+	Percentile        float64            `json:"percentile,omitempty"`
+	ErrorThresholds   Thresholds         `json:"errorThresholds"`
+	LatencyThresholds DurationThresholds `json:"latencyThresholds"`
+	Clock             clockwork.Clock    `json:"-"`
+
+	mu sync.Mutex
+
+	// samples is a ring buffer holding the count most recent outcomes,
+	// starting from the oldest at head.
+	samples []sample
+	head    int
+	count   int
+}
+
+// sample is the outcome of a single operation.
+type sample struct {
+	at      time.Time
+	failed  bool
+	latency time.Duration
+}
+
+// Record adds the outcome of an operation to the window.
+func (p *Passive) Record(err error, latency time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.samples == nil {
+		maxSamples := p.MaxSamples
+		if maxSamples <= 0 {
+			maxSamples = DefaultMaxSamples
+		}
+		p.samples = make([]sample, maxSamples)
+	}
+
+	next := sample{
+		at:      p.clock().Now(),
+		failed:  err != nil,
+		latency: latency,
+	}
+
+	// once full, the oldest outcome is overwritten
+	if p.count == len(p.samples) {
+		p.samples[p.head] = next
+		p.head = (p.head + 1) % len(p.samples)
+	} else {
+		p.samples[(p.head+p.count)%len(p.samples)] = next
+		p.count++
+	}
+
+	p.prune()
+}
+
+// Run evaluates the outcomes within the window.
+func (p *Passive) Run(ctx context.Context) Result {
+	percentile := p.Percentile
+	if percentile <= 0 || percentile > 1 {
+		percentile = DefaultPercentile
+	}
+
+	p.mu.Lock()
+	p.prune()
+
+	failures := 0
+	latencies := make([]time.Duration, p.count)
+	for i := range latencies {
+		s := p.samples[(p.head+i)%len(p.samples)]
+		if s.failed {
+			failures++
+		}
+		latencies[i] = s.latency
+	}
+	p.mu.Unlock()
+
+	details := map[string]interface{}{
+		"samples":  len(latencies),
+		"failures": failures,
+	}
+
+	if len(latencies) == 0 || len(latencies) < p.MinSamples {
+		return Result{
+			State:   state.OK,
+			Details: details,
+		}
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	latency := latencies[int(math.Ceil(percentile*float64(len(latencies))))-1]
+	errorRatio := float64(failures) / float64(len(latencies))
+
+	details["errorRatio"] = errorRatio
+	details["percentile"] = percentile
+	details["latency"] = latency.String()
+
+	resultState := state.Worst(
+		p.ErrorThresholds.Above(errorRatio),
+		p.LatencyThresholds.Above(latency),
+	)
+
+	if resultState == state.OK {
+		return Result{
+			State:   resultState,
+			Details: details,
+		}
+	}
+
+	return Result{
+		State: resultState,
+		Error: WrapError(fmt.Errorf("%d of %d operations failed, latency at p%g was %s",
+			failures, len(latencies), percentile*100, latency)),
+		Details: details,
+	}
+}
+
+// prune discards outcomes that fall outside of the window. The lock must be
+// held.
+func (p *Passive) prune() {
+	if p.Window <= 0 {
+		return
+	}
+
+	cutoff := p.clock().Now().Add(-p.Window)
+	for p.count > 0 && p.samples[p.head].at.Before(cutoff) {
+		p.head = (p.head + 1) % len(p.samples)
+		p.count--
+	}
+}
+
+func (p *Passive) clock() clockwork.Clock {
+	if p.Clock == nil {
+		return clockwork.NewRealClock()
+	}
+	return p.Clock
+}
+
+// RoundTripper wraps the provided transport (or http.DefaultTransport when
+// nil), recording the outcome of every request. Transport errors and server
+// errors (5xx) are recorded as failures. Requests canceled by the caller are
+// not recorded.
+func (p *Passive) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &passiveTransport{
+		passive: p,
+		next:    next,
+	}
+}
+
+type passiveTransport struct {
+	passive *Passive
+	next    http.RoundTripper
+}
+
+func (t *passiveTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	start := time.Now()
+	response, err := t.next.RoundTrip(request)
+	latency := time.Since(start)
+
+	switch {
+	case err != nil:
+		if errors.Is(request.Context().Err(), context.Canceled) {
+			return response, err
+		}
+		t.passive.Record(err, latency)
+	case response.StatusCode >= http.StatusInternalServerError:
+		t.passive.Record(fmt.Errorf("unexpected status %d", response.StatusCode), latency)
+	default:
+		t.passive.Record(nil, latency)
+	}
+
+	return response, err
+}
+
+// UnaryClientInterceptor records the outcome of every unary call made by a gRPC
+// client. Calls that fail with codes indicating a problem with the server
+// (e.g. Unavailable or Internal) are recorded as failures. Calls canceled by
+// the caller are not recorded.
+func (p *Passive) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		p.recordRPC(err, time.Since(start))
+		return err
+	}
+}
+
+// StreamClientInterceptor records the outcome of establishing every stream
+// opened by a gRPC client, using the same rules as UnaryClientInterceptor.
+func (p *Passive) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		p.recordRPC(err, time.Since(start))
+		return stream, err
+	}
+}
+
+func (p *Passive) recordRPC(err error, latency time.Duration) {
+	switch status.Code(err) {
+	case codes.Canceled:
+		return
+	case codes.Unknown, codes.DeadlineExceeded, codes.ResourceExhausted,
+		codes.Internal, codes.Unavailable, codes.DataLoss:
+		p.Record(err, latency)
+	default:
+		// other codes describe the request rather than the server
+		p.Record(nil, latency)
+	}
+}
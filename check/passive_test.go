@@ -0,0 +1,164 @@
+package check_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/state"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPassive(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+
+	passive := &check.Passive{
+		Window:     time.Minute,
+		MinSamples: 4,
+		Percentile: 0.5,
+		ErrorThresholds: check.Thresholds{
+			Minor:  0.25,
+			Outage: 0.5,
+		},
+		LatencyThresholds: check.DurationThresholds{
+			Major: time.Second,
+		},
+		Clock: clock,
+	}
+
+	passive.Record(fmt.Errorf("failed"), time.Millisecond)
+	passive.Record(fmt.Errorf("failed"), time.Millisecond)
+	passive.Record(nil, time.Millisecond)
+
+	{
+		// not enough samples
+		result := passive.Run(context.TODO())
+		require.Equal(t, state.OK, result.State)
+		require.Equal(t, 3, result.Details["samples"])
+	}
+
+	passive.Record(nil, time.Millisecond)
+
+	{
+		result := passive.Run(context.TODO())
+		require.Equal(t, state.Outage, result.State)
+		require.NotNil(t, result.Error)
+		require.Equal(t, 0.5, result.Details["errorRatio"])
+	}
+
+	// failures age out of the window
+	clock.Advance(time.Minute)
+	for i := 0; i < 4; i++ {
+		passive.Record(nil, 2*time.Second)
+	}
+
+	clock.Advance(time.Second)
+
+	{
+		result := passive.Run(context.TODO())
+		require.Equal(t, state.Major, result.State)
+		require.Equal(t, 4, result.Details["samples"])
+		require.Equal(t, "2s", result.Details["latency"])
+	}
+}
+
+func TestPassive_MaxSamples(t *testing.T) {
+	passive := &check.Passive{
+		MaxSamples: 2,
+		ErrorThresholds: check.Thresholds{
+			Outage: 0.5,
+		},
+	}
+
+	passive.Record(fmt.Errorf("failed"), time.Millisecond)
+	passive.Record(nil, time.Millisecond)
+	passive.Record(nil, time.Millisecond)
+
+	{
+		result := passive.Run(context.TODO())
+		require.Equal(t, state.OK, result.State)
+		require.Equal(t, 2, result.Details["samples"])
+		require.Equal(t, 0, result.Details["failures"])
+	}
+
+	// the oldest outcomes continue to be overwritten
+	passive.Record(fmt.Errorf("failed"), time.Millisecond)
+
+	{
+		result := passive.Run(context.TODO())
+		require.Equal(t, state.Outage, result.State)
+		require.Equal(t, 2, result.Details["samples"])
+		require.Equal(t, 1, result.Details["failures"])
+	}
+
+	passive.Record(nil, time.Millisecond)
+	passive.Record(nil, time.Millisecond)
+
+	{
+		result := passive.Run(context.TODO())
+		require.Equal(t, state.OK, result.State)
+		require.Equal(t, 0, result.Details["failures"])
+	}
+}
+
+func TestPassive_RoundTripper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	passive := &check.Passive{
+		ErrorThresholds: check.Thresholds{
+			Minor: 0.5,
+		},
+	}
+
+	client := &http.Client{Transport: passive.RoundTripper(nil)}
+
+	for _, path := range []string{"/ok", "/fail"} {
+		response, err := client.Get(server.URL + path)
+		require.NoError(t, err)
+		require.NoError(t, response.Body.Close())
+	}
+
+	result := passive.Run(context.TODO())
+	require.Equal(t, state.Minor, result.State)
+	require.Equal(t, 2, result.Details["samples"])
+	require.Equal(t, 1, result.Details["failures"])
+}
+
+func TestPassive_UnaryClientInterceptor(t *testing.T) {
+	passive := &check.Passive{
+		ErrorThresholds: check.Thresholds{
+			Major: 0.5,
+		},
+	}
+
+	interceptor := passive.UnaryClientInterceptor()
+
+	for _, code := range []codes.Code{codes.OK, codes.NotFound, codes.Unavailable, codes.Internal, codes.Canceled} {
+		err := interceptor(context.TODO(), "/greeter/Greet", nil, nil, nil,
+			func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				return status.Error(code, code.String())
+			})
+		require.Equal(t, code, status.Code(err))
+	}
+
+	result := passive.Run(context.TODO())
+	require.Equal(t, state.Major, result.State)
+	require.Equal(t, 4, result.Details["samples"])
+	require.Equal(t, 2, result.Details["failures"])
+}
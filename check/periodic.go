@@ -2,6 +2,8 @@ package check
 
 import (
 	"context"
+	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/jonboulle/clockwork"
@@ -12,14 +14,37 @@ import (
 // RunFunc is a simple function that defines a unary check for state.
 type RunFunc = func(ctx context.Context) (state.State, error)
 
-// Periodic is a Check implementation that runs a provided function on a set
-// interval and configured timeout. This is a common type of Check.
+// ResultFunc is a unary check that produces a full Result, allowing it to
+// include additional details. The Timestamp is populated by the caller. The
+// Run method of the provided checks (e.g. TCP, TLS, or SQL) is a ResultFunc.
+type ResultFunc = func(ctx context.Context) Result
+
+// Periodic is a Check implementation that runs a provided RunFunc or ResultFunc
+// on a set interval and configured timeout. This is a common type of Check.
 type Periodic struct {
 	Metadata
-	Interval time.Duration   `json:"interval,string"`
-	Timeout  time.Duration   `json:"timeout,string"`
-	Clock    clockwork.Clock `json:"-"`
-	RunFunc  RunFunc         `json:"-"`
+	Interval        time.Duration   `json:"interval,string"`
+	Timeout         time.Duration   `json:"timeout,string"`
+	InitialDelay    time.Duration   `json:"initialDelay,string,omitempty"`
+	Jitter          time.Duration   `json:"jitter,string,omitempty"`
+	FailureInterval time.Duration   `json:"failureInterval,string,omitempty"`
+	MaxBackoff      time.Duration   `json:"maxBackoff,string,omitempty"`
+	PanicState      state.State     `json:"panicState,omitempty"`
+	Clock           clockwork.Clock `json:"-"`
+	RunFunc         RunFunc         `json:"-"`
+	ResultFunc      ResultFunc      `json:"-"`
+
+	rand     *rand.Rand
+	failures uint
+	mu       sync.Mutex
+	inFlight *evaluation
+}
+
+// evaluation is a run of a Periodic check that is in progress.
+type evaluation struct {
+	done     chan struct{}
+	deadline time.Time
+	result   Result
 }
 
 // GetMetadata returns meta information about the check.
@@ -27,43 +52,146 @@ func (p *Periodic) GetMetadata() Metadata {
 	return p.Metadata
 }
 
-// Once performs a one time evaluation of the check.
+// Once performs a one time evaluation of the check. When both are set, the
+// ResultFunc is used instead of the RunFunc. Panics are recovered and reported
+// using PanicState, which defaults to Outage.
 func (p *Periodic) Once(parent context.Context) Result {
 	p.init()
 
+	start := p.Clock.Now()
+
 	ctx, cancel := context.WithTimeout(parent, p.Timeout)
 	defer cancel()
 
 	result := make(chan Result, 1)
 
 	go func() {
-		computedState, err := p.RunFunc(ctx)
-		result <- Result{
-			State:     computedState,
-			Error:     WrapError(err),
-			Timestamp: p.Clock.Now(),
-		}
+		r := p.join(ctx, start)
+		r.Timestamp = p.Clock.Now()
+		result <- r
 	}()
 
 	select {
 	case r := <-result:
 		return r
 	case <-p.Clock.After(p.Timeout):
-		return Result{
-			State:     state.Unknown,
-			Error:     WrapError(ErrTimeout),
-			Timestamp: p.Clock.Now(),
+		return p.timeout()
+	}
+}
+
+// evaluate performs a one time evaluation of the check on the calling go
+// routine. Unlike Once, a check that ignores its context will hold the caller
+// until it returns, though its result is still reported as a timeout.
+func (p *Periodic) evaluate(parent context.Context) Result {
+	p.init()
+
+	// like Once, timeouts are measured using the Clock
+	start := p.Clock.Now()
+
+	ctx, cancel := context.WithTimeout(parent, p.Timeout)
+	defer cancel()
+
+	result := p.join(ctx, start)
+	if p.Clock.Now().Sub(start) >= p.Timeout {
+		return p.timeout()
+	}
+
+	result.Timestamp = p.Clock.Now()
+	return result
+}
+
+// join runs the check, or waits for the evaluation already in progress and
+// shares its result. Evaluations are only shared until they time out, so that
+// a hung RunFunc does not hold every later evaluation. Panics are recovered and
+// reported as the result.
+func (p *Periodic) join(ctx context.Context, start time.Time) (result Result) {
+	p.mu.Lock()
+	if inFlight := p.inFlight; inFlight != nil && start.Before(inFlight.deadline) {
+		p.mu.Unlock()
+
+		select {
+		case <-inFlight.done:
+			return inFlight.result
+		case <-p.Clock.After(inFlight.deadline.Sub(start)):
+			return p.timeout()
+		}
+	}
+
+	inFlight := &evaluation{
+		done:     make(chan struct{}),
+		deadline: start.Add(p.Timeout),
+	}
+	p.inFlight = inFlight
+	p.mu.Unlock()
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			result = panicResult(recovered, p.PanicState, p.Clock)
+		}
+
+		p.mu.Lock()
+		if p.inFlight == inFlight {
+			p.inFlight = nil
 		}
+		p.mu.Unlock()
+
+		inFlight.result = result
+		close(inFlight.done)
+	}()
+
+	return p.run(ctx)
+}
+
+// timeout reports an evaluation that did not complete within the Timeout.
+func (p *Periodic) timeout() Result {
+	return Result{
+		State:     state.Unknown,
+		Error:     WrapError(ErrTimeout),
+		Timestamp: p.Clock.Now(),
+	}
+}
+
+// run invokes the configured ResultFunc or RunFunc.
+func (p *Periodic) run(ctx context.Context) Result {
+	if p.ResultFunc != nil {
+		return p.ResultFunc(ctx)
 	}
+
+	computedState, err := p.RunFunc(ctx)
+
+	// a nil *Error would produce a non-nil error interface
+	result := Result{State: computedState}
+	if err != nil {
+		result.Error = WrapError(err)
+	}
+	return result
 }
 
-// Watch sets up a go routine to run the check on the configured interval.
+// Trigger forces an immediate evaluation of the check. When an evaluation is
+// already in progress, its result is returned instead.
+func (p *Periodic) Trigger(ctx context.Context) Result {
+	return p.Once(ctx)
+}
+
+// Watch sets up a go routine to run the check on the configured interval. The
+// first evaluation is delayed by the InitialDelay, and each interval is
+// extended by a random amount up to Jitter. While the check is not OK, it's
+// evaluated on the FailureInterval, backing off up to MaxBackoff (or Interval
+// when unset).
 func (p *Periodic) Watch(ctx context.Context, channel chan Report) {
 	p.init()
 
 	stopCh := ctx.Done()
 
 	go func() {
+		if p.InitialDelay > 0 {
+			select {
+			case <-p.Clock.After(p.InitialDelay):
+			case <-stopCh:
+				return
+			}
+		}
+
 		for {
 			result := p.Once(ctx)
 			channel <- Report{
@@ -72,7 +200,7 @@ func (p *Periodic) Watch(ctx context.Context, channel chan Report) {
 			}
 
 			select {
-			case <-p.Clock.After(p.Interval):
+			case <-p.Clock.After(p.nextInterval(result)):
 				continue
 			case <-stopCh:
 				return
@@ -81,10 +209,47 @@ func (p *Periodic) Watch(ctx context.Context, channel chan Report) {
 	}()
 }
 
+// nextInterval computes how long to wait before the next evaluation given the
+// most recent result. It tracks consecutive failures in order to back off.
+func (p *Periodic) nextInterval(result Result) time.Duration {
+	interval := p.Interval
+
+	if result.State == state.OK || p.FailureInterval <= 0 {
+		p.failures = 0
+	} else {
+		limit := p.MaxBackoff
+		if limit <= 0 {
+			limit = p.Interval
+		}
+
+		interval = p.FailureInterval
+		for i := uint(0); i < p.failures && interval < limit; i++ {
+			interval *= 2
+		}
+
+		if interval > limit && limit >= p.FailureInterval {
+			interval = limit
+		}
+
+		p.failures++
+	}
+
+	if p.Jitter > 0 {
+		interval += time.Duration(p.rand.Int63n(int64(p.Jitter)))
+	}
+
+	return interval
+}
+
 func (p *Periodic) init() {
 	if p.Clock == nil {
 		p.Clock = clockwork.NewRealClock()
 	}
+
+	if p.rand == nil {
+		p.rand = rand.New(rand.NewSource(p.Clock.Now().UnixNano()))
+	}
 }
 
 var _ Check = &Periodic{}
+var _ Triggerable = &Periodic{}
@@ -0,0 +1,82 @@
+package check
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/mjpitz/go-gracefully/state"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriodic_nextInterval_Backoff(t *testing.T) {
+	periodic := &Periodic{
+		Interval:        time.Minute,
+		FailureInterval: time.Second,
+		MaxBackoff:      4 * time.Second,
+		Clock:           clockwork.NewFakeClock(),
+	}
+	periodic.init()
+
+	failing := Result{State: state.Outage}
+	healthy := Result{State: state.OK}
+
+	require.Equal(t, time.Second, periodic.nextInterval(failing))
+	require.Equal(t, 2*time.Second, periodic.nextInterval(failing))
+	require.Equal(t, 4*time.Second, periodic.nextInterval(failing))
+	require.Equal(t, 4*time.Second, periodic.nextInterval(failing))
+	require.Equal(t, time.Minute, periodic.nextInterval(healthy))
+	require.Equal(t, time.Second, periodic.nextInterval(failing))
+}
+
+func TestPeriodic_nextInterval_BackoffLimitedByInterval(t *testing.T) {
+	periodic := &Periodic{
+		Interval:        3 * time.Second,
+		FailureInterval: time.Second,
+		Clock:           clockwork.NewFakeClock(),
+	}
+	periodic.init()
+
+	failing := Result{State: state.Major}
+
+	require.Equal(t, time.Second, periodic.nextInterval(failing))
+	require.Equal(t, 2*time.Second, periodic.nextInterval(failing))
+	require.Equal(t, 3*time.Second, periodic.nextInterval(failing))
+}
+
+func TestPeriodic_nextInterval_Jitter(t *testing.T) {
+	periodic := &Periodic{
+		Interval: time.Minute,
+		Jitter:   time.Second,
+		Clock:    clockwork.NewFakeClock(),
+	}
+	periodic.init()
+
+	for i := 0; i < 100; i++ {
+		interval := periodic.nextInterval(Result{State: state.OK})
+		require.True(t, interval >= time.Minute)
+		require.True(t, interval < time.Minute+time.Second)
+	}
+}
+
+func TestPeriodic_evaluate_Timeout(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+
+	periodic := &Periodic{
+		Timeout: time.Second,
+		Clock:   clock,
+		RunFunc: func(ctx context.Context) (state.State, error) {
+			clock.Advance(time.Second)
+			return state.OK, nil
+		},
+	}
+
+	result := periodic.evaluate(context.TODO())
+	require.Equal(t, state.Unknown, result.State)
+	require.True(t, errors.Is(result.Error, ErrTimeout))
+	require.Equal(t, clock.Now(), result.Timestamp)
+}
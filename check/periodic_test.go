@@ -2,7 +2,9 @@ package check_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -25,7 +27,7 @@ func TestPeriodic_Once_Healthy(t *testing.T) {
 
 	result := healthy.Once(context.TODO())
 	require.Equal(t, state.OK, result.State)
-	require.Nil(t, result.Error)
+	require.True(t, result.Error == nil)
 }
 
 func TestPeriodic_Once_Unhealthy(t *testing.T) {
@@ -60,8 +62,61 @@ func TestPeriodic_Once_Timeout(t *testing.T) {
 	require.Equal(t, check.ErrTimeout, wrapped)
 }
 
+func TestPeriodic_Once_Hung(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	calls := int32(0)
+
+	hung := &check.Periodic{
+		Timeout: 50 * time.Millisecond,
+		RunFunc: func(ctx context.Context) (state.State, error) {
+			// the first call ignores its context
+			if atomic.AddInt32(&calls, 1) == 1 {
+				<-release
+			}
+			return state.OK, nil
+		},
+	}
+
+	result := hung.Once(context.TODO())
+	require.Equal(t, state.Unknown, result.State)
+
+	// evaluations that timed out are no longer shared
+	for i := 0; i < 3; i++ {
+		result = hung.Once(context.TODO())
+		require.Equal(t, state.OK, result.State)
+	}
+	require.Equal(t, int32(4), atomic.LoadInt32(&calls))
+}
+
+// timerClock notifies the test of each timer created by the check, allowing it
+// to wait for a specific timer before advancing the clock.
+type timerClock struct {
+	clockwork.FakeClock
+	timers chan time.Duration
+}
+
+func (c *timerClock) After(d time.Duration) <-chan time.Time {
+	ch := c.FakeClock.After(d)
+	c.timers <- d
+	return ch
+}
+
+// waitFor blocks until a timer for the provided duration has been created.
+func (c *timerClock) waitFor(d time.Duration) {
+	for duration := range c.timers {
+		if duration == d {
+			return
+		}
+	}
+}
+
 func TestPeriodic_Watch(t *testing.T) {
-	clock := clockwork.NewFakeClock()
+	clock := &timerClock{
+		FakeClock: clockwork.NewFakeClock(),
+		timers:    make(chan time.Duration, 16),
+	}
 
 	responses := []state.State{
 		state.Outage,
@@ -96,6 +151,8 @@ func TestPeriodic_Watch(t *testing.T) {
 		require.Nil(t, report.Result.Error)
 	}
 
+	// the interval must be scheduled before advancing the clock
+	clock.waitFor(time.Second)
 	clock.Advance(time.Second)
 
 	{
@@ -104,6 +161,7 @@ func TestPeriodic_Watch(t *testing.T) {
 		require.Nil(t, report.Result.Error)
 	}
 
+	clock.waitFor(time.Second)
 	clock.Advance(time.Second)
 
 	{
@@ -112,6 +170,7 @@ func TestPeriodic_Watch(t *testing.T) {
 		require.Nil(t, report.Result.Error)
 	}
 
+	clock.waitFor(time.Second)
 	clock.Advance(time.Second)
 
 	{
@@ -120,3 +179,53 @@ func TestPeriodic_Watch(t *testing.T) {
 		require.Nil(t, report.Result.Error)
 	}
 }
+
+func TestPeriodic_Watch_InitialDelay(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	start := clock.Now()
+
+	periodic := &check.Periodic{
+		Interval:     time.Second,
+		Timeout:      time.Second * 10,
+		InitialDelay: time.Second * 5,
+		Clock:        clock,
+		RunFunc: func(ctx context.Context) (state.State, error) {
+			return state.OK, nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	reportChan := make(chan check.Report, 1)
+
+	periodic.Watch(ctx, reportChan)
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Second * 5)
+
+	report := <-reportChan
+	require.Equal(t, state.OK, report.Result.State)
+	require.Equal(t, start.Add(time.Second*5), report.Result.Timestamp)
+}
+
+func TestPeriodic_Once_Panic(t *testing.T) {
+	panics := &check.Periodic{
+		Timeout:    time.Second * 10,
+		PanicState: state.Major,
+		Clock:      clockwork.NewFakeClock(),
+		RunFunc: func(ctx context.Context) (state.State, error) {
+			panic("boom")
+		},
+	}
+
+	result := panics.Once(context.TODO())
+	require.Equal(t, state.Major, result.State)
+	require.NotNil(t, result.Error)
+
+	panicErr := &check.PanicError{}
+	require.True(t, errors.As(result.Error, &panicErr))
+	require.Equal(t, "boom", panicErr.Value)
+	require.Contains(t, string(panicErr.Stack), "periodic_test.go")
+	require.Equal(t, "panic: boom", result.Error.Error())
+}
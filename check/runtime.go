@@ -0,0 +1,118 @@
+package check
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/mjpitz/go-gracefully/state"
+)
+
+// Goroutines maps the number of go routines in the process onto a state. It
+// helps detect go routine leaks.
+type Goroutines struct {
+	Thresholds Thresholds `json:"thresholds"`
+}
+
+// Run performs a one time evaluation of the process.
+func (g *Goroutines) Run(ctx context.Context) Result {
+	count := runtime.NumGoroutine()
+
+	return Result{
+		State: g.Thresholds.Above(float64(count)),
+		Details: map[string]interface{}{
+			"goroutines": count,
+		},
+	}
+}
+
+// Heap maps the number of bytes in in-use heap spans onto a state. Reading
+// memory statistics briefly stops the world, so avoid evaluating this check
+// too frequently.
+type Heap struct {
+	Thresholds Thresholds `json:"thresholds"`
+}
+
+// Run performs a one time evaluation of the process.
+func (h *Heap) Run(ctx context.Context) Result {
+	stats := &runtime.MemStats{}
+	runtime.ReadMemStats(stats)
+
+	return Result{
+		State: h.Thresholds.Above(float64(stats.HeapInuse)),
+		Details: map[string]interface{}{
+			"heapAlloc":   stats.HeapAlloc,
+			"heapInuse":   stats.HeapInuse,
+			"heapSys":     stats.HeapSys,
+			"heapObjects": stats.HeapObjects,
+			"sys":         stats.Sys,
+		},
+	}
+}
+
+// GCPause maps the duration of the most recent garbage collection pause onto a
+// state. Reading memory statistics briefly stops the world, so avoid
+// evaluating this check too frequently.
+type GCPause struct {
+	Thresholds DurationThresholds `json:"thresholds"`
+}
+
+// Run performs a one time evaluation of the process.
+func (g *GCPause) Run(ctx context.Context) Result {
+	stats := &runtime.MemStats{}
+	runtime.ReadMemStats(stats)
+
+	lastPause := time.Duration(0)
+	if stats.NumGC > 0 {
+		lastPause = time.Duration(stats.PauseNs[(stats.NumGC+255)%256])
+	}
+
+	return Result{
+		State: g.Thresholds.Above(lastPause),
+		Details: map[string]interface{}{
+			"lastPause":     lastPause.String(),
+			"pauseTotal":    time.Duration(stats.PauseTotalNs).String(),
+			"numGC":         stats.NumGC,
+			"gcCPUFraction": stats.GCCPUFraction,
+		},
+	}
+}
+
+// FileDescriptors maps the number of open file descriptors onto a state, both
+// as an absolute count and as a fraction of the soft limit for the process.
+// It's currently only supported on Linux, where descriptors are counted using
+// /proc/self/fd. Other platforms report an Unknown state.
+type FileDescriptors struct {
+	Thresholds            Thresholds `json:"thresholds"`
+	UtilizationThresholds Thresholds `json:"utilizationThresholds"`
+}
+
+// Run performs a one time evaluation of the process.
+func (f *FileDescriptors) Run(ctx context.Context) Result {
+	open, limit, err := fileDescriptors()
+	if err != nil {
+		return Result{
+			State: state.Unknown,
+			Error: WrapError(err),
+		}
+	}
+
+	details := map[string]interface{}{
+		"open":  open,
+		"limit": limit,
+	}
+
+	utilization := float64(0)
+	if limit > 0 {
+		utilization = float64(open) / float64(limit)
+		details["utilization"] = utilization
+	}
+
+	return Result{
+		State: state.Worst(
+			f.Thresholds.Above(float64(open)),
+			f.UtilizationThresholds.Above(utilization),
+		),
+		Details: details,
+	}
+}
@@ -0,0 +1,50 @@
+package check_test
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/state"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoroutines(t *testing.T) {
+	require.Equal(t, state.OK, (&check.Goroutines{}).Run(context.TODO()).State)
+
+	result := (&check.Goroutines{Thresholds: check.Thresholds{Major: 1}}).Run(context.TODO())
+	require.Equal(t, state.Major, result.State)
+	require.True(t, result.Details["goroutines"].(int) > 0)
+}
+
+func TestHeap(t *testing.T) {
+	result := (&check.Heap{Thresholds: check.Thresholds{Outage: 1}}).Run(context.TODO())
+	require.Equal(t, state.Outage, result.State)
+	require.True(t, result.Details["heapInuse"].(uint64) > 0)
+}
+
+func TestGCPause(t *testing.T) {
+	runtime.GC()
+
+	result := (&check.GCPause{}).Run(context.TODO())
+	require.Equal(t, state.OK, result.State)
+	require.True(t, result.Details["numGC"].(uint32) > 0)
+}
+
+func TestFileDescriptors(t *testing.T) {
+	result := (&check.FileDescriptors{
+		Thresholds:            check.Thresholds{Minor: 1},
+		UtilizationThresholds: check.Thresholds{Outage: 1},
+	}).Run(context.TODO())
+
+	if runtime.GOOS != "linux" {
+		require.Equal(t, state.Unknown, result.State)
+		return
+	}
+
+	require.Equal(t, state.Minor, result.State)
+	require.True(t, result.Details["open"].(int) > 0)
+	require.True(t, result.Details["limit"].(uint64) > 0)
+}
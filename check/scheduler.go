@@ -0,0 +1,177 @@
+package check
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+)
+
+// NewScheduler constructs a Scheduler that evaluates Periodic checks using the
+// provided number of workers. Schedulers must be started.
+func NewScheduler(clock clockwork.Clock, workers int) *Scheduler {
+	if workers < 1 {
+		workers = 1
+	}
+
+	return &Scheduler{
+		clock:   clock,
+		workers: workers,
+		mu:      &sync.Mutex{},
+		queue:   make(schedule, 0),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Scheduler drives the evaluation of many Periodic checks from a single timer
+// heap and a bounded pool of workers. This avoids the go routine per check
+// (and per evaluation) that Periodic.Watch requires. A check is only placed
+// back on the heap once its evaluation completes, so a slow RunFunc never
+// overlaps itself.
+type Scheduler struct {
+	clock   clockwork.Clock
+	workers int
+
+	mu    *sync.Mutex
+	queue schedule
+	wake  chan struct{}
+}
+
+// Schedule registers a Periodic check with the scheduler. Reports for the
+// check are written to the provided channel. Checks can be scheduled before or
+// after the scheduler has been started.
+func (s *Scheduler) Schedule(periodic *Periodic, channel chan Report) {
+	periodic.init()
+
+	s.mu.Lock()
+	heap.Push(&s.queue, &scheduled{
+		periodic: periodic,
+		channel:  channel,
+		next:     s.clock.Now().Add(periodic.InitialDelay),
+	})
+	s.mu.Unlock()
+
+	s.notify()
+}
+
+// Start launches the workers and the timer loop. Both stop once the provided
+// context is done.
+func (s *Scheduler) Start(ctx context.Context) {
+	jobs := make(chan *scheduled, s.workers)
+
+	for i := 0; i < s.workers; i++ {
+		go s.work(ctx, jobs)
+	}
+
+	go s.loop(ctx, jobs)
+}
+
+func (s *Scheduler) loop(ctx context.Context, jobs chan *scheduled) {
+	stopCh := ctx.Done()
+
+	// only replace the timer when an earlier deadline is scheduled
+	var timer <-chan time.Time
+	var deadline time.Time
+
+	for {
+		due := make([]*scheduled, 0)
+
+		s.mu.Lock()
+		now := s.clock.Now()
+		for len(s.queue) > 0 && !s.queue[0].next.After(now) {
+			due = append(due, heap.Pop(&s.queue).(*scheduled))
+		}
+
+		if len(due) == 0 && len(s.queue) > 0 && (timer == nil || s.queue[0].next.Before(deadline)) {
+			deadline = s.queue[0].next
+			timer = s.clock.After(deadline.Sub(now))
+		}
+		s.mu.Unlock()
+
+		for _, entry := range due {
+			select {
+			case jobs <- entry:
+			case <-stopCh:
+				return
+			}
+		}
+
+		if len(due) > 0 {
+			continue
+		}
+
+		select {
+		case <-timer:
+			timer = nil
+		case <-s.wake:
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+func (s *Scheduler) work(ctx context.Context, jobs chan *scheduled) {
+	stopCh := ctx.Done()
+
+	for {
+		select {
+		case entry := <-jobs:
+			result := entry.periodic.evaluate(ctx)
+
+			select {
+			case entry.channel <- Report{Check: entry.periodic, Result: result}:
+			case <-stopCh:
+				return
+			}
+
+			s.mu.Lock()
+			entry.next = s.clock.Now().Add(entry.periodic.nextInterval(result))
+			heap.Push(&s.queue, entry)
+			s.mu.Unlock()
+
+			s.notify()
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// notify wakes the timer loop so that it can recompute the next deadline.
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+type scheduled struct {
+	periodic *Periodic
+	channel  chan Report
+	next     time.Time
+}
+
+// schedule is a min-heap of scheduled checks ordered by their next evaluation.
+type schedule []*scheduled
+
+func (s schedule) Len() int { return len(s) }
+
+func (s schedule) Less(i, j int) bool { return s[i].next.Before(s[j].next) }
+
+func (s schedule) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+func (s *schedule) Push(x interface{}) {
+	*s = append(*s, x.(*scheduled))
+}
+
+func (s *schedule) Pop() interface{} {
+	old := *s
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	*s = old[:n-1]
+	return entry
+}
+
+var _ heap.Interface = &schedule{}
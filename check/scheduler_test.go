@@ -0,0 +1,216 @@
+package check_test
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/state"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	reportChan := make(chan check.Report, 2)
+
+	scheduler := check.NewScheduler(clock, 2)
+	for i := 1; i <= 2; i++ {
+		scheduler.Schedule(&check.Periodic{
+			Metadata: check.Metadata{
+				Name: fmt.Sprintf("periodic-%d", i),
+			},
+			Interval: time.Duration(i) * time.Second,
+			Timeout:  time.Second,
+			Clock:    clock,
+			RunFunc: func(ctx context.Context) (state.State, error) {
+				return state.OK, nil
+			},
+		}, reportChan)
+	}
+	scheduler.Start(ctx)
+
+	timestamps := make(map[string][]time.Time)
+	collect := func() {
+		for {
+			select {
+			case report := <-reportChan:
+				name := report.Check.GetMetadata().Name
+				timestamps[name] = append(timestamps[name], report.Result.Timestamp)
+			case <-time.After(50 * time.Millisecond):
+				return
+			}
+		}
+	}
+
+	collect()
+	for i := 0; i < 6; i++ {
+		clock.Advance(time.Second)
+		collect()
+	}
+
+	require.True(t, len(timestamps["periodic-1"]) > len(timestamps["periodic-2"]))
+	require.True(t, len(timestamps["periodic-2"]) > 1)
+
+	for name, interval := range map[string]time.Duration{"periodic-1": time.Second, "periodic-2": 2 * time.Second} {
+		for i := 1; i < len(timestamps[name]); i++ {
+			require.True(t, timestamps[name][i].Sub(timestamps[name][i-1]) >= interval)
+		}
+	}
+}
+
+func TestScheduler_NoOverlap(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	release := make(chan struct{})
+	running := int32(0)
+	overlapped := int32(0)
+
+	reportChan := make(chan check.Report, 1)
+
+	scheduler := check.NewScheduler(clock, 4)
+	scheduler.Schedule(&check.Periodic{
+		Interval: time.Second,
+		Timeout:  time.Minute,
+		Clock:    clock,
+		RunFunc: func(ctx context.Context) (state.State, error) {
+			if atomic.AddInt32(&running, 1) > 1 {
+				atomic.StoreInt32(&overlapped, 1)
+			}
+			defer atomic.AddInt32(&running, -1)
+
+			<-release
+			return state.OK, nil
+		},
+	}, reportChan)
+	scheduler.Start(ctx)
+
+	// advance well past several intervals while the first evaluation is blocked
+	for i := 0; i < 5; i++ {
+		clock.Advance(time.Second)
+	}
+
+	close(release)
+	<-reportChan
+
+	require.Equal(t, int32(0), atomic.LoadInt32(&overlapped))
+}
+
+func TestScheduler_Trigger(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	runs := int32(0)
+
+	periodic := &check.Periodic{
+		Interval: time.Minute,
+		Timeout:  time.Minute,
+		Clock:    clock,
+		RunFunc: func(ctx context.Context) (state.State, error) {
+			if atomic.AddInt32(&runs, 1) > 1 {
+				return state.Major, nil
+			}
+
+			close(started)
+			<-release
+			return state.OK, nil
+		},
+	}
+
+	reportChan := make(chan check.Report, 1)
+
+	scheduler := check.NewScheduler(clock, 1)
+	scheduler.Schedule(periodic, reportChan)
+	scheduler.Start(ctx)
+
+	<-started
+
+	triggered := make(chan check.Result, 1)
+	go func() {
+		triggered <- periodic.Trigger(ctx)
+	}()
+
+	// give the trigger a chance to overlap the scheduled evaluation
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	require.Equal(t, state.OK, (<-reportChan).Result.State)
+	require.Equal(t, state.OK, (<-triggered).State)
+	require.Equal(t, int32(1), atomic.LoadInt32(&runs))
+}
+
+const benchmarkChecks = 1000
+
+func benchmarkPeriodic(ran *int64) *check.Periodic {
+	return &check.Periodic{
+		Interval: time.Millisecond,
+		Timeout:  time.Second,
+		RunFunc: func(ctx context.Context) (state.State, error) {
+			atomic.AddInt64(ran, 1)
+			return state.OK, nil
+		},
+	}
+}
+
+func BenchmarkPeriodic_Watch(b *testing.B) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	ran := int64(0)
+	reportChan := make(chan check.Report, benchmarkChecks)
+	baseline := runtime.NumGoroutine()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < benchmarkChecks; i++ {
+		benchmarkPeriodic(&ran).Watch(ctx, reportChan)
+	}
+
+	for i := 0; i < b.N; i++ {
+		<-reportChan
+	}
+
+	b.ReportMetric(float64(runtime.NumGoroutine()-baseline), "goroutines")
+}
+
+func BenchmarkScheduler(b *testing.B) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	ran := int64(0)
+	reportChan := make(chan check.Report, benchmarkChecks)
+	baseline := runtime.NumGoroutine()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	scheduler := check.NewScheduler(clockwork.NewRealClock(), runtime.NumCPU())
+	for i := 0; i < benchmarkChecks; i++ {
+		scheduler.Schedule(benchmarkPeriodic(&ran), reportChan)
+	}
+	scheduler.Start(ctx)
+
+	for i := 0; i < b.N; i++ {
+		<-reportChan
+	}
+
+	b.ReportMetric(float64(runtime.NumGoroutine()-baseline), "goroutines")
+}
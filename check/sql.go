@@ -0,0 +1,99 @@
+package check
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/mjpitz/go-gracefully/state"
+)
+
+// SQL verifies that a database can be reached. When a Query is provided, it's
+// used to validate the connection. Otherwise, the database is pinged. The state
+// is derived from the latency of the validation, the utilization of the
+// connection pool (open connections relative to the maximum), and the number
+// of new waits for a connection since the previous evaluation. Statistics for
+// the pool are recorded in the details of each result.
+type SQL struct {
+	DB                    *sql.DB            `json:"-"`
+	Query                 string             `json:"query,omitempty"`
+	LatencyThresholds     DurationThresholds `json:"latencyThresholds"`
+	UtilizationThresholds Thresholds         `json:"utilizationThresholds"`
+	WaitThresholds        Thresholds         `json:"waitThresholds"`
+
+	mu        sync.Mutex
+	baselined bool
+	waitCount int64
+}
+
+// Run performs a one time evaluation of the database.
+func (s *SQL) Run(ctx context.Context) Result {
+	start := time.Now()
+	err := s.validate(ctx)
+	latency := time.Since(start)
+
+	stats := s.DB.Stats()
+
+	s.mu.Lock()
+	if !s.baselined {
+		// waits from before the first evaluation are not counted
+		s.baselined = true
+		s.waitCount = stats.WaitCount
+	}
+	waits := stats.WaitCount - s.waitCount
+	s.waitCount = stats.WaitCount
+	s.mu.Unlock()
+
+	details := map[string]interface{}{
+		"latency":            latency.String(),
+		"maxOpenConnections": stats.MaxOpenConnections,
+		"openConnections":    stats.OpenConnections,
+		"inUse":              stats.InUse,
+		"idle":               stats.Idle,
+		"waitCount":          stats.WaitCount,
+		"waitDuration":       stats.WaitDuration.String(),
+		"maxIdleClosed":      stats.MaxIdleClosed,
+		"maxLifetimeClosed":  stats.MaxLifetimeClosed,
+	}
+
+	if err != nil {
+		return Result{
+			State:   state.Outage,
+			Error:   WrapError(err),
+			Details: details,
+		}
+	}
+
+	utilization := float64(0)
+	if stats.MaxOpenConnections > 0 {
+		utilization = float64(stats.OpenConnections) / float64(stats.MaxOpenConnections)
+		details["utilization"] = utilization
+	}
+
+	return Result{
+		State: state.Worst(
+			s.LatencyThresholds.Above(latency),
+			s.UtilizationThresholds.Above(utilization),
+			s.WaitThresholds.Above(float64(waits)),
+		),
+		Details: details,
+	}
+}
+
+func (s *SQL) validate(ctx context.Context) error {
+	if s.Query == "" {
+		return s.DB.PingContext(ctx)
+	}
+
+	rows, err := s.DB.QueryContext(ctx, s.Query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,170 @@
+package check_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/state"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDriver is a minimal database/sql driver. Pings and queries fail when
+// the data source name is "down".
+type fakeDriver struct{}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	return &fakeConn{down: name == "down"}, nil
+}
+
+type fakeConn struct {
+	down bool
+}
+
+func (c *fakeConn) Ping(ctx context.Context) error {
+	if c.down {
+		return fmt.Errorf("connection refused")
+	}
+	return nil
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	if c.down {
+		return nil, fmt.Errorf("connection refused")
+	}
+	return &fakeStmt{}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, fmt.Errorf("not supported") }
+
+type fakeStmt struct{}
+
+func (s *fakeStmt) Close() error { return nil }
+
+func (s *fakeStmt) NumInput() int { return 0 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &fakeRows{}, nil
+}
+
+type fakeRows struct {
+	read bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"1"} }
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.read {
+		return io.EOF
+	}
+	r.read = true
+	dest[0] = int64(1)
+	return nil
+}
+
+func init() {
+	sql.Register("fake", &fakeDriver{})
+}
+
+func TestSQL(t *testing.T) {
+	db, err := sql.Open("fake", "up")
+	require.NoError(t, err)
+	defer db.Close()
+
+	db.SetMaxOpenConns(1)
+
+	{
+		result := (&check.SQL{DB: db}).Run(context.TODO())
+		require.Equal(t, state.OK, result.State)
+		require.Equal(t, 1, result.Details["maxOpenConnections"])
+		require.Equal(t, 1, result.Details["openConnections"])
+	}
+
+	{
+		result := (&check.SQL{DB: db, Query: "SELECT 1"}).Run(context.TODO())
+		require.Equal(t, state.OK, result.State)
+	}
+
+	{
+		result := (&check.SQL{
+			DB:                    db,
+			UtilizationThresholds: check.Thresholds{Major: 1},
+		}).Run(context.TODO())
+		require.Equal(t, state.Major, result.State)
+		require.Equal(t, float64(1), result.Details["utilization"])
+	}
+
+	{
+		result := (&check.SQL{
+			DB:                db,
+			LatencyThresholds: check.DurationThresholds{Minor: time.Nanosecond},
+		}).Run(context.TODO())
+		require.Equal(t, state.Minor, result.State)
+	}
+}
+
+func TestSQL_WaitCount(t *testing.T) {
+	db, err := sql.Open("fake", "up")
+	require.NoError(t, err)
+	defer db.Close()
+
+	db.SetMaxOpenConns(1)
+
+	chk := &check.SQL{
+		DB:             db,
+		WaitThresholds: check.Thresholds{Minor: 1},
+	}
+
+	require.Equal(t, state.OK, chk.Run(context.TODO()).State)
+
+	// hold the only connection so that the next evaluation must wait for it
+	conn, err := db.Conn(context.TODO())
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = conn.Close()
+	}()
+
+	require.Equal(t, state.Minor, chk.Run(context.TODO()).State)
+	require.Equal(t, state.OK, chk.Run(context.TODO()).State)
+
+	// waits from before the first evaluation are not counted
+	fresh := &check.SQL{
+		DB:             db,
+		WaitThresholds: check.Thresholds{Minor: 1},
+	}
+
+	require.Equal(t, state.OK, fresh.Run(context.TODO()).State)
+}
+
+func TestSQL_Down(t *testing.T) {
+	db, err := sql.Open("fake", "down")
+	require.NoError(t, err)
+	defer db.Close()
+
+	{
+		result := (&check.SQL{DB: db}).Run(context.TODO())
+		require.Equal(t, state.Outage, result.State)
+		require.Equal(t, "connection refused", result.Error.Error())
+	}
+
+	{
+		result := (&check.SQL{DB: db, Query: "SELECT 1"}).Run(context.TODO())
+		require.Equal(t, state.Outage, result.State)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package check
+
+func statfs(path string) (*fsStats, error) {
+	return nil, ErrUnsupported
+}
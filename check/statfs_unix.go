@@ -0,0 +1,24 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package check
+
+import (
+	"syscall"
+)
+
+func statfs(path string) (*fsStats, error) {
+	stat := &syscall.Statfs_t{}
+	if err := syscall.Statfs(path, stat); err != nil {
+		return nil, err
+	}
+
+	blockSize := uint64(stat.Bsize)
+
+	return &fsStats{
+		totalBytes:  uint64(stat.Blocks) * blockSize,
+		freeBytes:   uint64(stat.Bavail) * blockSize,
+		totalInodes: uint64(stat.Files),
+		freeInodes:  uint64(stat.Ffree),
+	}, nil
+}
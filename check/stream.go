@@ -2,18 +2,65 @@ package check
 
 import (
 	"context"
+	"time"
 
 	"github.com/jonboulle/clockwork"
+
+	"github.com/mjpitz/go-gracefully/state"
 )
 
 // WatchFunc defines an easy to use function that starts a watch.
 type WatchFunc = func(ctx context.Context, channel chan Result)
 
-// Stream is a Check implementation
+// DefaultRestartBackoff is the initial delay between restarts of a Stream when
+// its RestartPolicy has no Backoff configured.
+const DefaultRestartBackoff = time.Second
+
+// RestartPolicy configures how a Stream re-invokes its WatchFunc after it
+// returns or goes stale. The delay between restarts starts at Backoff
+// (DefaultRestartBackoff when unset) and doubles for each restart that did not
+// produce a result, up to MaxBackoff.
+type RestartPolicy struct {
+	Backoff    time.Duration `json:"backoff,string,omitempty"`
+	MaxBackoff time.Duration `json:"maxBackoff,string,omitempty"`
+}
+
+// delay computes how long to wait before the next restart given the number
+// of consecutive restarts that failed to produce a result.
+func (r *RestartPolicy) delay(failures uint) time.Duration {
+	delay := r.Backoff
+	if delay <= 0 {
+		// restarting without a delay would spin on a failing WatchFunc
+		delay = DefaultRestartBackoff
+	}
+
+	for i := uint(0); i < failures && (r.MaxBackoff <= 0 || delay < r.MaxBackoff); i++ {
+		delay *= 2
+	}
+
+	if r.MaxBackoff > 0 && delay > r.MaxBackoff {
+		delay = r.MaxBackoff
+	}
+
+	return delay
+}
+
+// Stream is a Check implementation that observes results pushed by a
+// WatchFunc. The WatchFunc is invoked on its own go routine. Panics raised by
+// it are recovered and reported using PanicState, which defaults to Outage.
+// Panics raised on go routines spawned by the WatchFunc cannot be recovered.
+//
+// When MaxSilence is set, the stream reports an Unknown state if no result is
+// received within that duration. When a Restart policy is set, the WatchFunc
+// is re-invoked whenever it returns or goes stale. The number of restarts is
+// recorded in the details of each subsequent result.
 type Stream struct {
 	Metadata
-	Clock     clockwork.Clock `json:"-"`
-	WatchFunc WatchFunc       `json:"-"`
+	MaxSilence time.Duration   `json:"maxSilence,string,omitempty"`
+	Restart    *RestartPolicy  `json:"restart,omitempty"`
+	PanicState state.State     `json:"panicState,omitempty"`
+	Clock      clockwork.Clock `json:"-"`
+	WatchFunc  WatchFunc       `json:"-"`
 }
 
 // GetMetadata returns meta information about the check.
@@ -27,25 +74,169 @@ func (s *Stream) Watch(ctx context.Context, channel chan Report) {
 		s.Clock = clockwork.NewRealClock()
 	}
 
-	results := make(chan Result, 1)
-	s.WatchFunc(ctx, results)
+	go func() {
+		stopCh := ctx.Done()
+		restarts := uint64(0)
+		failures := uint(0)
 
+		for {
+			watchCtx, cancel := context.WithCancel(ctx)
+			received := s.forward(ctx, channel, s.invoke(watchCtx), restarts)
+			cancel()
+
+			if ctx.Err() != nil || s.Restart == nil {
+				return
+			}
+
+			if received {
+				failures = 0
+			}
+
+			select {
+			case <-s.Clock.After(s.Restart.delay(failures)):
+			case <-stopCh:
+				return
+			}
+
+			failures++
+			restarts++
+		}
+	}()
+}
+
+// invoke calls the WatchFunc on its own go routine and relays its results.
+// When a restart policy is configured, the returned channel is closed once the
+// WatchFunc returns. Otherwise, results continue to be relayed from any go
+// routines the WatchFunc may have spawned.
+func (s *Stream) invoke(ctx context.Context) chan Result {
 	stopCh := ctx.Done()
+	results := make(chan Result, 1)
+	done := make(chan Result, 1)
+
+	go func() {
+		defer close(done)
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				done <- panicResult(recovered, s.PanicState, s.Clock)
+			}
+		}()
+
+		s.WatchFunc(ctx, results)
+	}()
+
+	relayed := make(chan Result)
+	relay := func(result Result) bool {
+		select {
+		case relayed <- result:
+			return true
+		case <-stopCh:
+			return false
+		}
+	}
 
 	go func() {
+		defer close(relayed)
+
 		for {
 			select {
 			case result := <-results:
-				result.Timestamp = s.Clock.Now()
-				channel <- Report{
-					Check:  s,
-					Result: result,
+				if !relay(result) {
+					return
+				}
+			case result, ok := <-done:
+				if !ok {
+					done = nil
+
+					if s.Restart == nil {
+						continue
+					}
+
+					// deliver anything sent just before the WatchFunc returned
+					select {
+					case result := <-results:
+						relay(result)
+					default:
+					}
+					return
+				}
+
+				if !relay(result) {
+					return
 				}
 			case <-stopCh:
 				return
 			}
 		}
 	}()
+
+	return relayed
+}
+
+// forward relays results to the channel until the context is done. When a
+// restart policy is configured, it also stops once the WatchFunc returns or
+// goes stale. It reports whether any result was received.
+func (s *Stream) forward(ctx context.Context, channel chan Report, results chan Result, restarts uint64) bool {
+	stopCh := ctx.Done()
+	received := false
+	last := s.Clock.Now()
+
+	var silence <-chan time.Time
+	if s.MaxSilence > 0 {
+		silence = s.Clock.After(s.MaxSilence)
+	}
+
+	emit := func(result Result) {
+		result.Timestamp = s.Clock.Now()
+
+		if restarts > 0 {
+			details := make(map[string]interface{}, len(result.Details)+1)
+			for key, value := range result.Details {
+				details[key] = value
+			}
+			details["restarts"] = restarts
+			result.Details = details
+		}
+
+		channel <- Report{
+			Check:  s,
+			Result: result,
+		}
+	}
+
+	for {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				return received
+			}
+
+			received = true
+			last = s.Clock.Now()
+			emit(result)
+
+			if silence == nil && s.MaxSilence > 0 {
+				silence = s.Clock.After(s.MaxSilence)
+			}
+		case <-silence:
+			if elapsed := s.Clock.Now().Sub(last); elapsed < s.MaxSilence {
+				silence = s.Clock.After(s.MaxSilence - elapsed)
+				continue
+			}
+
+			emit(Result{
+				State: state.Unknown,
+				Error: WrapError(ErrStale),
+			})
+
+			if s.Restart != nil {
+				return received
+			}
+
+			silence = nil
+		case <-stopCh:
+			return received
+		}
+	}
 }
 
 var _ Check = &Stream{}
@@ -0,0 +1,20 @@
+package check
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestartPolicy_delay(t *testing.T) {
+	policy := &RestartPolicy{
+		MaxBackoff: 3 * time.Second,
+	}
+
+	// an unset backoff uses the default rather than restarting immediately
+	require.Equal(t, DefaultRestartBackoff, policy.delay(0))
+	require.Equal(t, 2*DefaultRestartBackoff, policy.delay(1))
+	require.Equal(t, 3*time.Second, policy.delay(2))
+	require.Equal(t, 3*time.Second, policy.delay(10))
+}
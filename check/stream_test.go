@@ -0,0 +1,131 @@
+package check_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/state"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStream_Watch(t *testing.T) {
+	stream := &check.Stream{
+		Clock: clockwork.NewFakeClock(),
+		WatchFunc: func(ctx context.Context, channel chan check.Result) {
+			for _, s := range []state.State{state.Major, state.OK} {
+				channel <- check.Result{State: s}
+			}
+			<-ctx.Done()
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	reportChan := make(chan check.Report, 1)
+	stream.Watch(ctx, reportChan)
+
+	require.Equal(t, state.Major, (<-reportChan).Result.State)
+	require.Equal(t, state.OK, (<-reportChan).Result.State)
+}
+
+func TestStream_Watch_Panic(t *testing.T) {
+	stream := &check.Stream{
+		Clock: clockwork.NewFakeClock(),
+		WatchFunc: func(ctx context.Context, channel chan check.Result) {
+			panic("boom")
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	reportChan := make(chan check.Report, 1)
+	stream.Watch(ctx, reportChan)
+
+	report := <-reportChan
+	require.Equal(t, state.Outage, report.Result.State)
+
+	panicErr := &check.PanicError{}
+	require.True(t, errors.As(report.Result.Error, &panicErr))
+	require.Equal(t, "boom", panicErr.Value)
+}
+
+func TestStream_Watch_Stale(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+
+	stream := &check.Stream{
+		MaxSilence: 10 * time.Second,
+		Clock:      clock,
+		WatchFunc: func(ctx context.Context, channel chan check.Result) {
+			channel <- check.Result{State: state.OK}
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	reportChan := make(chan check.Report, 1)
+	stream.Watch(ctx, reportChan)
+
+	require.Equal(t, state.OK, (<-reportChan).Result.State)
+
+	clock.BlockUntil(1)
+	clock.Advance(10 * time.Second)
+
+	report := <-reportChan
+	require.Equal(t, state.Unknown, report.Result.State)
+	require.True(t, errors.Is(report.Result.Error, check.ErrStale))
+}
+
+func TestStream_Watch_Restart(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	invocations := int32(0)
+
+	stream := &check.Stream{
+		MaxSilence: 10 * time.Second,
+		Restart: &check.RestartPolicy{
+			Backoff: time.Second,
+		},
+		Clock: clock,
+		WatchFunc: func(ctx context.Context, channel chan check.Result) {
+			if atomic.AddInt32(&invocations, 1) == 1 {
+				// the first invocation goes silent
+				<-ctx.Done()
+				return
+			}
+
+			// subsequent invocations report once and return
+			channel <- check.Result{State: state.OK}
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	reportChan := make(chan check.Report, 1)
+	stream.Watch(ctx, reportChan)
+
+	clock.BlockUntil(1)
+	clock.Advance(10 * time.Second)
+
+	report := <-reportChan
+	require.Equal(t, state.Unknown, report.Result.State)
+	require.True(t, errors.Is(report.Result.Error, check.ErrStale))
+	require.Nil(t, report.Result.Details)
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+
+	report = <-reportChan
+	require.Equal(t, state.OK, report.Result.State)
+	require.Equal(t, uint64(1), report.Result.Details["restarts"])
+	require.Equal(t, int32(2), atomic.LoadInt32(&invocations))
+}
@@ -0,0 +1,97 @@
+package check
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"time"
+
+	"github.com/mjpitz/go-gracefully/state"
+)
+
+// TCP verifies that a TCP connection can be established with an address. When
+// a Banner is provided, the first line sent by the server must match it.
+//
+//	&check.Periodic{
+//	    Metadata:   check.Metadata{Name: "redis", Weight: 10},
+//	    Interval:   time.Second * 5,
+//	    Timeout:    time.Second,
+//	    ResultFunc: (&check.TCP{Address: "localhost:6379"}).Run,
+//	}
+type TCP struct {
+	Address     string         `json:"address"`
+	DialTimeout time.Duration  `json:"dialTimeout,string,omitempty"`
+	Banner      *regexp.Regexp `json:"-"`
+}
+
+// Run performs a one time evaluation of the address.
+func (t *TCP) Run(ctx context.Context) Result {
+	details := map[string]interface{}{
+		"address": t.Address,
+	}
+
+	start := time.Now()
+
+	conn, err := dial(ctx, t.Address, t.DialTimeout)
+	if err != nil {
+		return Result{
+			State:   state.Outage,
+			Error:   WrapError(err),
+			Details: details,
+		}
+	}
+	defer conn.Close()
+
+	details["latency"] = time.Since(start).String()
+
+	if t.Banner != nil {
+		banner, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil && banner == "" {
+			return Result{
+				State:   state.Outage,
+				Error:   WrapError(err),
+				Details: details,
+			}
+		}
+
+		details["banner"] = banner
+
+		if !t.Banner.MatchString(banner) {
+			return Result{
+				State:   state.Outage,
+				Error:   WrapError(fmt.Errorf("banner did not match %q", t.Banner.String())),
+				Details: details,
+			}
+		}
+	}
+
+	return Result{
+		State:   state.OK,
+		Details: details,
+	}
+}
+
+// dial establishes a TCP connection within the provided timeout. The
+// connection inherits the deadline of the provided context, if any.
+func dial(ctx context.Context, address string, timeout time.Duration) (net.Conn, error) {
+	dialCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		dialCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(dialCtx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,63 @@
+package check_test
+
+import (
+	"context"
+	"net"
+	"regexp"
+	"testing"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/state"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			_, _ = conn.Write([]byte("+OK ready\n"))
+			_ = conn.Close()
+		}
+	}()
+
+	address := listener.Addr().String()
+
+	{
+		result := (&check.TCP{Address: address}).Run(context.TODO())
+		require.Equal(t, state.OK, result.State)
+		require.Equal(t, address, result.Details["address"])
+	}
+
+	{
+		result := (&check.TCP{Address: address, Banner: regexp.MustCompile(`^\+OK`)}).Run(context.TODO())
+		require.Equal(t, state.OK, result.State)
+		require.Equal(t, "+OK ready\n", result.Details["banner"])
+	}
+
+	{
+		result := (&check.TCP{Address: address, Banner: regexp.MustCompile(`^SSH-`)}).Run(context.TODO())
+		require.Equal(t, state.Outage, result.State)
+		require.NotNil(t, result.Error)
+	}
+}
+
+func TestTCP_Unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	address := listener.Addr().String()
+	_ = listener.Close()
+
+	result := (&check.TCP{Address: address}).Run(context.TODO())
+	require.Equal(t, state.Outage, result.State)
+	require.NotNil(t, result.Error)
+}
@@ -0,0 +1,63 @@
+package check
+
+import (
+	"time"
+
+	"github.com/mjpitz/go-gracefully/state"
+)
+
+// Thresholds map a measured value onto a state. A threshold of zero is
+// disabled.
+type Thresholds struct {
+	Minor  float64 `json:"minor,omitempty"`
+	Major  float64 `json:"major,omitempty"`
+	Outage float64 `json:"outage,omitempty"`
+}
+
+// Above returns the most severe state whose threshold is met or exceeded by the
+// value. It's used for measurements where larger values are worse (e.g.
+// latency or utilization).
+func (t Thresholds) Above(value float64) state.State {
+	switch {
+	case t.Outage != 0 && value >= t.Outage:
+		return state.Outage
+	case t.Major != 0 && value >= t.Major:
+		return state.Major
+	case t.Minor != 0 && value >= t.Minor:
+		return state.Minor
+	}
+	return state.OK
+}
+
+// Below returns the most severe state whose threshold is met or undercut by the
+// value. It's used for measurements where smaller values are worse (e.g. free
+// space or time until expiry).
+func (t Thresholds) Below(value float64) state.State {
+	switch {
+	case t.Outage != 0 && value <= t.Outage:
+		return state.Outage
+	case t.Major != 0 && value <= t.Major:
+		return state.Major
+	case t.Minor != 0 && value <= t.Minor:
+		return state.Minor
+	}
+	return state.OK
+}
+
+// DurationThresholds map a measured duration onto a state. A threshold of zero
+// is disabled.
+type DurationThresholds struct {
+	Minor  time.Duration `json:"minor,string,omitempty"`
+	Major  time.Duration `json:"major,string,omitempty"`
+	Outage time.Duration `json:"outage,string,omitempty"`
+}
+
+// Above returns the most severe state whose threshold is met or exceeded by the
+// duration.
+func (t DurationThresholds) Above(value time.Duration) state.State {
+	return Thresholds{
+		Minor:  float64(t.Minor),
+		Major:  float64(t.Major),
+		Outage: float64(t.Outage),
+	}.Above(float64(value))
+}
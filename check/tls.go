@@ -0,0 +1,155 @@
+package check
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/mjpitz/go-gracefully/state"
+)
+
+// DefaultExpiryThresholds are the number of days until a certificate expires
+// at which a TLS check degrades.
+var DefaultExpiryThresholds = Thresholds{
+	Minor:  30,
+	Major:  14,
+	Outage: 7,
+}
+
+// TLS performs a TLS handshake with an address and inspects the certificate
+// presented by the server. The number of days until the certificate expires is
+// mapped onto a state using the ExpiryThresholds (DefaultExpiryThresholds when
+// unset). Expired certificates and failed handshakes are always an Outage.
+type TLS struct {
+	Address          string          `json:"address"`
+	DialTimeout      time.Duration   `json:"dialTimeout,string,omitempty"`
+	ExpiryThresholds *Thresholds     `json:"expiryThresholds,omitempty"`
+	Config           *tls.Config     `json:"-"`
+	Clock            clockwork.Clock `json:"-"`
+}
+
+// Run performs a one time evaluation of the address.
+func (t *TLS) Run(ctx context.Context) Result {
+	if t.Clock == nil {
+		t.Clock = clockwork.NewRealClock()
+	}
+
+	details := map[string]interface{}{
+		"address": t.Address,
+	}
+
+	conn, err := dial(ctx, t.Address, t.DialTimeout)
+	if err != nil {
+		return Result{
+			State:   state.Outage,
+			Error:   WrapError(err),
+			Details: details,
+		}
+	}
+	defer conn.Close()
+
+	tlsConn := tls.Client(conn, t.config())
+	if err := tlsConn.Handshake(); err != nil {
+		// rejected certificates (e.g. once expired) are still described
+		if leaf := rejectedCertificate(err); leaf != nil {
+			describeCertificate(details, leaf, t.Clock.Now())
+		}
+
+		return Result{
+			State:   state.Outage,
+			Error:   WrapError(err),
+			Details: details,
+		}
+	}
+
+	certificates := tlsConn.ConnectionState().PeerCertificates
+	if len(certificates) == 0 {
+		return Result{
+			State:   state.Outage,
+			Error:   WrapError(fmt.Errorf("no certificates presented by %s", t.Address)),
+			Details: details,
+		}
+	}
+
+	leaf := certificates[0]
+	days := describeCertificate(details, leaf, t.Clock.Now())
+
+	if days <= 0 {
+		return Result{
+			State:   state.Outage,
+			Error:   WrapError(fmt.Errorf("certificate expired on %s", leaf.NotAfter.UTC().Format(time.RFC3339))),
+			Details: details,
+		}
+	}
+
+	thresholds := DefaultExpiryThresholds
+	if t.ExpiryThresholds != nil {
+		thresholds = *t.ExpiryThresholds
+	}
+
+	return Result{
+		State:   thresholds.Below(days),
+		Details: details,
+	}
+}
+
+// config returns the tls.Config used for the handshake, defaulting the
+// ServerName to the host portion of the Address. Certificates are verified
+// against the time of the Clock.
+func (t *TLS) config() *tls.Config {
+	config := &tls.Config{}
+	if t.Config != nil {
+		config = t.Config.Clone()
+	}
+
+	config.Time = t.Clock.Now
+
+	if config.ServerName == "" {
+		if host, _, err := net.SplitHostPort(t.Address); err == nil {
+			config.ServerName = host
+		}
+	}
+
+	return config
+}
+
+// describeCertificate adds the subject, issuer, and expiry of the certificate to
+// the details, returning the number of days until it expires.
+func describeCertificate(details map[string]interface{}, leaf *x509.Certificate, now time.Time) float64 {
+	days := leaf.NotAfter.Sub(now).Hours() / 24
+
+	details["subject"] = leaf.Subject.String()
+	details["issuer"] = leaf.Issuer.String()
+	details["notAfter"] = leaf.NotAfter.UTC().Format(time.RFC3339)
+	details["daysUntilExpiry"] = math.Floor(days)
+
+	return days
+}
+
+// rejectedCertificate returns the certificate that failed verification, if the
+// handshake failed because of one.
+func rejectedCertificate(err error) *x509.Certificate {
+	invalid := x509.CertificateInvalidError{}
+	if errors.As(err, &invalid) {
+		return invalid.Cert
+	}
+
+	unknownAuthority := x509.UnknownAuthorityError{}
+	if errors.As(err, &unknownAuthority) {
+		return unknownAuthority.Cert
+	}
+
+	hostname := x509.HostnameError{}
+	if errors.As(err, &hostname) {
+		return hostname.Certificate
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package check_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/state"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	certificate := server.Certificate()
+	pool := x509.NewCertPool()
+	pool.AddCert(certificate)
+
+	address := server.Listener.Addr().String()
+	day := 24 * time.Hour
+
+	tests := []struct {
+		remaining time.Duration
+		expected  state.State
+	}{
+		{remaining: 60 * day, expected: state.OK},
+		{remaining: 20 * day, expected: state.Minor},
+		{remaining: 10 * day, expected: state.Major},
+		{remaining: 3 * day, expected: state.Outage},
+	}
+
+	for _, test := range tests {
+		result := (&check.TLS{
+			Address: address,
+			Config: &tls.Config{
+				RootCAs:    pool,
+				ServerName: "example.com",
+			},
+			Clock: clockwork.NewFakeClockAt(certificate.NotAfter.Add(-test.remaining)),
+		}).Run(context.TODO())
+
+		require.Equal(t, test.expected, result.State, test.remaining.String())
+		require.Equal(t, certificate.Subject.String(), result.Details["subject"])
+		require.Equal(t, certificate.Issuer.String(), result.Details["issuer"])
+		require.Equal(t, certificate.NotAfter.UTC().Format(time.RFC3339), result.Details["notAfter"])
+	}
+
+	// expired certificates fail verification, but are still described
+	{
+		result := (&check.TLS{
+			Address: address,
+			Config: &tls.Config{
+				RootCAs:    pool,
+				ServerName: "example.com",
+			},
+			Clock: clockwork.NewFakeClockAt(certificate.NotAfter.Add(day)),
+		}).Run(context.TODO())
+
+		require.Equal(t, state.Outage, result.State)
+		require.Equal(t, certificate.Subject.String(), result.Details["subject"])
+
+		invalid := x509.CertificateInvalidError{}
+		require.True(t, errors.As(result.Error, &invalid))
+		require.Equal(t, x509.Expired, invalid.Reason)
+		require.Equal(t, float64(-1), result.Details["daysUntilExpiry"])
+	}
+}
+
+func TestTLS_UntrustedCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	result := (&check.TLS{
+		Address: server.Listener.Addr().String(),
+	}).Run(context.TODO())
+
+	require.Equal(t, state.Outage, result.State)
+	require.NotNil(t, result.Error)
+	require.Equal(t, server.Certificate().Subject.String(), result.Details["subject"])
+}
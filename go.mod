@@ -4,8 +4,12 @@ go 1.14
 
 require (
 	github.com/google/uuid v1.1.2
+	github.com/gorilla/websocket v1.4.2
 	github.com/jonboulle/clockwork v0.1.0
 	github.com/stretchr/testify v1.6.1
+	golang.org/x/net v0.0.0-20190311183353-d8887717615a
+	google.golang.org/genproto v0.0.0-20200806141610-86f49bd18e98
 	google.golang.org/grpc v1.34.0
 	google.golang.org/grpc/examples v0.0.0-20201209011439-fd32f6a4fefe
+	google.golang.org/protobuf v1.25.0
 )
@@ -0,0 +1,43 @@
+package health
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/mjpitz/go-gracefully/state"
+)
+
+// DefaultRetryAfter is the amount of time callers are asked to wait before
+// retrying a rejected request when no RetryAfter is configured.
+const DefaultRetryAfter = 30 * time.Second
+
+// admit decides whether a request should be served given the health of the
+// named checks, or of the system when no names are provided. Requests are
+// rejected while in an Outage. When the state is as bad as shedFrom (if set),
+// requests are also rejected with a probability of one minus the current HP.
+// Checks that have never reported are excluded, so requests are admitted while
+// the checks start up. Unregistered check names are rejected.
+func (m *Monitor) admit(names []string, shedFrom state.State) (state.State, error) {
+	hp, reported, err := m.summary.reportedHP(names)
+	if err != nil {
+		return state.Unknown, err
+	}
+
+	if !reported {
+		return state.Unknown, nil
+	}
+
+	current := state.ForScore(hp)
+
+	switch {
+	case current == state.OK:
+		return current, nil
+	case current == state.Outage:
+		return current, fmt.Errorf("%w: %s", ErrUnavailable, current)
+	case shedFrom != "" && state.Score(current) <= state.Score(shedFrom) && rand.Float32() >= hp:
+		return current, fmt.Errorf("%w: %s", ErrShed, current)
+	}
+
+	return current, nil
+}
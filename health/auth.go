@@ -0,0 +1,88 @@
+package health
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// Authenticator determines whether the caller of a request may view the full
+// health report.
+type Authenticator = func(request *http.Request) bool
+
+// WithAuthenticator restricts the full report to callers accepted by the
+// Authenticator. Other callers only receive the state of the system, and are
+// denied access to the check endpoints. When several authenticators are
+// configured, callers need only be accepted by one of them.
+func WithAuthenticator(authenticator Authenticator) HandlerOption {
+	return func(options *handlerOptions) {
+		options.authenticators = append(options.authenticators, authenticator)
+	}
+}
+
+// WithBearerToken restricts the full report to callers presenting one of the
+// tokens in the Authorization header (e.g. "Bearer <token>").
+func WithBearerToken(tokens ...string) HandlerOption {
+	return func(options *handlerOptions) {
+		options.challenge = "Bearer"
+		options.authenticators = append(options.authenticators, func(request *http.Request) bool {
+			header := request.Header.Get("Authorization")
+			if len(header) < 7 || !strings.EqualFold(header[:7], "Bearer ") {
+				return false
+			}
+
+			presented := []byte(strings.TrimSpace(header[7:]))
+			for _, token := range tokens {
+				if subtle.ConstantTimeCompare(presented, []byte(token)) == 1 {
+					return true
+				}
+			}
+			return false
+		})
+	}
+}
+
+// WithClientCertificate restricts the full report to callers presenting a
+// verified TLS client certificate whose subject common name, or full subject,
+// is one of the provided subjects. The server must be configured to verify
+// client certificates (e.g. tls.VerifyClientCertIfGiven).
+func WithClientCertificate(subjects ...string) HandlerOption {
+	return func(options *handlerOptions) {
+		options.authenticators = append(options.authenticators, func(request *http.Request) bool {
+			if request.TLS == nil || len(request.TLS.VerifiedChains) == 0 {
+				return false
+			}
+
+			subject := request.TLS.VerifiedChains[0][0].Subject
+			for _, allowed := range subjects {
+				if allowed == subject.CommonName || allowed == subject.String() {
+					return true
+				}
+			}
+			return false
+		})
+	}
+}
+
+// authenticated reports whether the caller may view the full report. All
+// callers are authenticated when no authenticators are configured.
+func (o *handlerOptions) authenticated(request *http.Request) bool {
+	if len(o.authenticators) == 0 {
+		return true
+	}
+
+	for _, authenticator := range o.authenticators {
+		if authenticator(request) {
+			return true
+		}
+	}
+	return false
+}
+
+// unauthorized rejects a request from a caller that is not authenticated.
+func (o *handlerOptions) unauthorized(writer http.ResponseWriter) {
+	if o.challenge != "" {
+		writer.Header().Set("WWW-Authenticate", o.challenge)
+	}
+	http.Error(writer, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+}
@@ -0,0 +1,315 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/state"
+)
+
+// DefaultBreakerCooldown is the amount of time an open Breaker waits before
+// allowing trial calls when no Cooldown is configured.
+const DefaultBreakerCooldown = 10 * time.Second
+
+// BreakerState describes whether a Breaker is allowing calls.
+type BreakerState string
+
+const (
+	// BreakerClosed allows all calls.
+	BreakerClosed BreakerState = "closed"
+
+	// BreakerOpen rejects all calls.
+	BreakerOpen BreakerState = "open"
+
+	// BreakerHalfOpen allows a limited number of trial calls.
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// Breaker is a circuit breaker that opens while its dependency is in an Outage
+// or too many calls fail, and reports its own state when registered as a Check.
+type Breaker struct {
+	check.Metadata
+	Check          check.Check     `json:"-"`
+	Monitor        *Monitor        `json:"-"`
+	CheckName      string          `json:"checkName,omitempty"`
+	ErrorThreshold float64         `json:"errorThreshold,omitempty"`
+	MinCalls       int             `json:"minCalls,omitempty"`
+	Window         time.Duration   `json:"window,string,omitempty"`
+	Cooldown       time.Duration   `json:"cooldown,string,omitempty"`
+	HalfOpenCalls  int             `json:"halfOpenCalls,omitempty"`
+	OpenState      state.State     `json:"openState,omitempty"`
+	Clock          clockwork.Clock `json:"-"`
+
+	once    sync.Once
+	mu      sync.Mutex
+	changes chan struct{}
+
+	state       BreakerState
+	cause       error
+	health      state.State
+	openedAt    time.Time
+	windowStart time.Time
+	calls       int
+	failures    int
+	trials      int
+	successes   int
+}
+
+// GetMetadata returns meta information about the check.
+func (b *Breaker) GetMetadata() check.Metadata {
+	return b.Metadata
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() BreakerState {
+	b.init()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// Allow returns ErrBreakerOpen when the call should not be made. Every allowed
+// call must be followed by a call to Done. Once the Cooldown passes, up to
+// HalfOpenCalls trial calls are allowed, closing the breaker if they succeed.
+func (b *Breaker) Allow() error {
+	b.init()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.Clock.Now()
+
+	if b.state == BreakerOpen {
+		if b.health == state.Outage || now.Sub(b.openedAt) < b.cooldown() {
+			return ErrBreakerOpen
+		}
+
+		b.transition(BreakerHalfOpen, b.cause)
+	}
+
+	if b.state == BreakerHalfOpen {
+		if b.trials >= b.halfOpenCalls() {
+			return ErrBreakerOpen
+		}
+
+		b.trials++
+	}
+
+	return nil
+}
+
+// Done records the outcome of a call that was allowed.
+func (b *Breaker) Done(err error) {
+	b.init()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.Clock.Now()
+
+	switch b.state {
+	case BreakerHalfOpen:
+		if err != nil {
+			b.open(now, err)
+			return
+		}
+
+		b.successes++
+		if b.successes >= b.halfOpenCalls() {
+			b.transition(BreakerClosed, nil)
+		}
+	case BreakerClosed:
+		if b.Window > 0 && now.Sub(b.windowStart) >= b.Window {
+			b.reset(now)
+		}
+
+		b.calls++
+		if err != nil {
+			b.failures++
+		}
+
+		if b.ErrorThreshold <= 0 || b.calls < b.MinCalls {
+			return
+		}
+
+		if ratio := float64(b.failures) / float64(b.calls); ratio >= b.ErrorThreshold {
+			b.open(now, fmt.Errorf("error ratio %.2f reached threshold %.2f", ratio, b.ErrorThreshold))
+		}
+	}
+}
+
+// Watch observes the health of the dependency, from the wrapped Check or the
+// named check of the Monitor, and reports the state of the breaker as it
+// changes. A wrapped Check should not also be registered with a Monitor.
+func (b *Breaker) Watch(ctx context.Context, channel chan check.Report) {
+	b.init()
+
+	switch {
+	case b.Check != nil:
+		reports := make(chan check.Report, 1)
+		b.Check.Watch(ctx, reports)
+		go b.observe(ctx, reports)
+	case b.Monitor != nil:
+		reports, unsubscribe := b.Monitor.Subscribe()
+
+		if current, ok := b.Monitor.Report().Results[b.CheckName]; ok {
+			b.observeHealth(current.LastCheck)
+		}
+
+		go func() {
+			defer unsubscribe()
+			b.observe(ctx, reports)
+		}()
+	}
+
+	go func() {
+		stopCh := ctx.Done()
+		last := BreakerState("")
+
+		for {
+			b.mu.Lock()
+			current := b.state
+			result := b.result()
+			b.mu.Unlock()
+
+			if current != last {
+				last = current
+
+				select {
+				case channel <- check.Report{Check: b, Result: result}:
+				case <-stopCh:
+					return
+				}
+			}
+
+			select {
+			case <-b.changes:
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// observe updates the breaker with the results of the dependency. It never
+// blocks on the breaker's own reports, as they may flow through the Monitor
+// producing the reports being observed.
+func (b *Breaker) observe(ctx context.Context, reports chan check.Report) {
+	stopCh := ctx.Done()
+
+	for {
+		select {
+		case r := <-reports:
+			if b.Check != nil || (r.Check != nil && r.Check.GetMetadata().Name == b.CheckName) {
+				b.observeHealth(r.Result)
+			}
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+func (b *Breaker) observeHealth(result check.Result) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.health = result.State
+	if result.State != state.Outage || b.state == BreakerOpen {
+		return
+	}
+
+	cause := fmt.Errorf("dependency is in an outage")
+	if result.Error != nil {
+		cause = fmt.Errorf("dependency is in an outage: %w", result.Error)
+	}
+
+	b.open(b.Clock.Now(), cause)
+}
+
+// result describes the breaker as a check result. The lock must be held.
+func (b *Breaker) result() check.Result {
+	result := check.Result{
+		State:     state.OK,
+		Timestamp: b.Clock.Now(),
+		Details: map[string]interface{}{
+			"breaker": b.state,
+		},
+	}
+
+	switch b.state {
+	case BreakerOpen:
+		result.State = b.OpenState
+		if result.State == "" {
+			result.State = state.Outage
+		}
+		result.Error = check.WrapError(fmt.Errorf("%w: %v", ErrBreakerOpen, b.cause))
+	case BreakerHalfOpen:
+		result.State = state.Minor
+	}
+
+	return result
+}
+
+// open trips the breaker. The lock must be held.
+func (b *Breaker) open(now time.Time, cause error) {
+	b.openedAt = now
+	b.transition(BreakerOpen, cause)
+}
+
+// transition moves the breaker into a new state, resetting all counters. The
+// lock must be held.
+func (b *Breaker) transition(next BreakerState, cause error) {
+	b.state = next
+	b.cause = cause
+	b.trials = 0
+	b.successes = 0
+	b.reset(b.Clock.Now())
+
+	select {
+	case b.changes <- struct{}{}:
+	default:
+	}
+}
+
+// reset starts a new window for counting calls. The lock must be held.
+func (b *Breaker) reset(now time.Time) {
+	b.windowStart = now
+	b.calls = 0
+	b.failures = 0
+}
+
+func (b *Breaker) cooldown() time.Duration {
+	if b.Cooldown <= 0 {
+		return DefaultBreakerCooldown
+	}
+	return b.Cooldown
+}
+
+func (b *Breaker) halfOpenCalls() int {
+	if b.HalfOpenCalls <= 0 {
+		return 1
+	}
+	return b.HalfOpenCalls
+}
+
+func (b *Breaker) init() {
+	b.once.Do(func() {
+		if b.Clock == nil {
+			b.Clock = clockwork.NewRealClock()
+		}
+
+		b.changes = make(chan struct{}, 1)
+		b.state = BreakerClosed
+		b.health = state.Unknown
+		b.windowStart = b.Clock.Now()
+	})
+}
+
+var _ check.Check = &Breaker{}
@@ -0,0 +1,114 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/health"
+	"github.com/mjpitz/go-gracefully/state"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreaker_ErrorThreshold(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+
+	breaker := &health.Breaker{
+		ErrorThreshold: 0.5,
+		MinCalls:       4,
+		Cooldown:       time.Second,
+		HalfOpenCalls:  2,
+		Clock:          clock,
+	}
+
+	for _, err := range []error{nil, fmt.Errorf("failed"), nil} {
+		require.NoError(t, breaker.Allow())
+		breaker.Done(err)
+	}
+	require.Equal(t, health.BreakerClosed, breaker.State())
+
+	require.NoError(t, breaker.Allow())
+	breaker.Done(fmt.Errorf("failed"))
+	require.Equal(t, health.BreakerOpen, breaker.State())
+	require.True(t, errors.Is(breaker.Allow(), health.ErrBreakerOpen))
+
+	// a failed trial opens the breaker again
+	clock.Advance(time.Second)
+	require.NoError(t, breaker.Allow())
+	require.Equal(t, health.BreakerHalfOpen, breaker.State())
+	breaker.Done(fmt.Errorf("failed"))
+	require.Equal(t, health.BreakerOpen, breaker.State())
+
+	// successful trials close the breaker
+	clock.Advance(time.Second)
+	require.NoError(t, breaker.Allow())
+	require.NoError(t, breaker.Allow())
+	require.True(t, errors.Is(breaker.Allow(), health.ErrBreakerOpen))
+
+	breaker.Done(nil)
+	require.Equal(t, health.BreakerHalfOpen, breaker.State())
+	breaker.Done(nil)
+	require.Equal(t, health.BreakerClosed, breaker.State())
+}
+
+func TestBreaker_Monitor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	clock := clockwork.NewFakeClock()
+	dependency, states := newControlledMonitor(ctx, t)
+
+	breaker := &health.Breaker{
+		Metadata: check.Metadata{
+			Name:   "breaker",
+			Weight: 10,
+		},
+		Monitor:   dependency,
+		CheckName: "controlled",
+		Cooldown:  time.Second,
+		Clock:     clock,
+	}
+
+	monitor := health.NewMonitor(breaker)
+	require.NoError(t, monitor.SetClock(clock))
+
+	reports, unsubscribe := monitor.Subscribe()
+	defer unsubscribe()
+
+	require.NoError(t, monitor.Start(ctx))
+
+	// one report for the breaker, one for the system
+	require.Equal(t, state.OK, (<-reports).Result.State)
+	require.Equal(t, state.OK, (<-reports).Result.State)
+
+	states <- state.Outage
+
+	{
+		report := <-reports
+		require.Equal(t, state.Outage, report.Result.State)
+		require.True(t, errors.Is(report.Result.Error, health.ErrBreakerOpen))
+		require.Equal(t, state.Outage, (<-reports).Result.State)
+	}
+
+	// the breaker remains open during the outage, regardless of the cooldown
+	clock.Advance(time.Second)
+	require.True(t, errors.Is(breaker.Allow(), health.ErrBreakerOpen))
+
+	states <- state.OK
+	require.Eventually(t, func() bool {
+		return breaker.Allow() == nil
+	}, time.Second, time.Millisecond)
+
+	require.Equal(t, state.Minor, (<-reports).Result.State)
+	require.Equal(t, state.Minor, (<-reports).Result.State)
+
+	breaker.Done(nil)
+	require.Equal(t, state.OK, (<-reports).Result.State)
+	require.Equal(t, state.OK, (<-reports).Result.State)
+}
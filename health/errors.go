@@ -5,4 +5,22 @@ import "fmt"
 var (
 	// ErrAlreadyStarted is returned when the Monitor has alreadybeen started
 	ErrAlreadyStarted = fmt.Errorf("monitor already started")
+
+	// ErrUnknownCheck is returned when a check name is not registered with the Monitor
+	ErrUnknownCheck = fmt.Errorf("unknown check")
+
+	// ErrNotTriggerable is returned when a check cannot be evaluated on demand
+	ErrNotTriggerable = fmt.Errorf("check cannot be triggered")
+
+	// ErrRateLimited is returned when a check is triggered more often than allowed
+	ErrRateLimited = fmt.Errorf("check triggered too frequently")
+
+	// ErrBreakerOpen is returned when a Breaker rejects a call
+	ErrBreakerOpen = fmt.Errorf("circuit breaker is open")
+
+	// ErrUnavailable is returned when a request is rejected due to an outage
+	ErrUnavailable = fmt.Errorf("service unavailable")
+
+	// ErrShed is returned when a request is rejected to shed load while degraded
+	ErrShed = fmt.Errorf("request shed")
 )
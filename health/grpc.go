@@ -0,0 +1,105 @@
+package health
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/mjpitz/go-gracefully/state"
+)
+
+// exemptServices are never rejected by a GRPCInterceptor so that the server
+// can still be probed and inspected while unhealthy.
+var exemptServices = []string{
+	"grpc.health.v1.Health",
+	"grpc.reflection.v1alpha.ServerReflection",
+	"grpc.reflection.v1.ServerReflection",
+}
+
+// GRPCInterceptor rejects gRPC requests based on the health reported by a
+// Monitor. Requests are rejected with codes.Unavailable while in an Outage,
+// along with a RetryInfo detail asking callers to wait RetryAfter (defaulting
+// to DefaultRetryAfter). When Shed is set, requests are also rejected with a
+// probability proportional to the missing HP while degraded.
+//
+// By default, requests are admitted based on the system state. Methods maps
+// full method names ("/package.Service/Method") or services
+// ("package.Service") to the checks they depend on, in which case the worst
+// of their states is used instead. The health and reflection services, along
+// with any additional Exempt services or methods, are always admitted.
+type GRPCInterceptor struct {
+	Monitor    *Monitor
+	Methods    map[string][]string
+	Exempt     []string
+	RetryAfter time.Duration
+	Shed       bool
+}
+
+// Unary returns an interceptor for unary requests.
+func (i *GRPCInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		if err := i.admit(info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Stream returns an interceptor for streaming requests.
+func (i *GRPCInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		if err := i.admit(info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func (i *GRPCInterceptor) admit(fullMethod string) error {
+	service := strings.SplitN(strings.TrimPrefix(fullMethod, "/"), "/", 2)[0]
+
+	for _, exempts := range [][]string{exemptServices, i.Exempt} {
+		for _, exempt := range exempts {
+			if exempt == service || exempt == fullMethod {
+				return nil
+			}
+		}
+	}
+
+	names, ok := i.Methods[fullMethod]
+	if !ok {
+		names = i.Methods[service]
+	}
+
+	shedFrom := state.State("")
+	if i.Shed {
+		shedFrom = state.Minor
+	}
+
+	_, err := i.Monitor.admit(names, shedFrom)
+	if err == nil {
+		return nil
+	}
+
+	retryAfter := i.RetryAfter
+	if retryAfter <= 0 {
+		retryAfter = DefaultRetryAfter
+	}
+
+	unavailable := status.New(codes.Unavailable, err.Error())
+	if detailed, detailErr := unavailable.WithDetails(&errdetails.RetryInfo{
+		RetryDelay: durationpb.New(retryAfter),
+	}); detailErr == nil {
+		unavailable = detailed
+	}
+
+	return unavailable.Err()
+}
@@ -0,0 +1,103 @@
+package health_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/mjpitz/go-gracefully/health"
+	"github.com/mjpitz/go-gracefully/state"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGRPCInterceptor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	monitor, states := newControlledMonitor(ctx, t)
+
+	reports, unsubscribe := monitor.Subscribe()
+	defer unsubscribe()
+
+	interceptor := (&health.GRPCInterceptor{
+		Monitor: monitor,
+		Methods: map[string][]string{
+			"greeter.Greeter": {"controlled"},
+		},
+		RetryAfter: time.Minute,
+	}).Unary()
+
+	call := func(method string) error {
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				return "ok", nil
+			})
+		return err
+	}
+
+	// unknown state is admitted
+	require.NoError(t, call("/greeter.Greeter/Greet"))
+
+	states <- state.Outage
+	<-reports
+	<-reports
+
+	{
+		err := call("/greeter.Greeter/Greet")
+		require.Equal(t, codes.Unavailable, status.Code(err))
+
+		details := status.Convert(err).Details()
+		require.Len(t, details, 1)
+		require.Equal(t, int64(60), details[0].(*errdetails.RetryInfo).RetryDelay.Seconds)
+	}
+
+	require.NoError(t, call("/grpc.health.v1.Health/Check"))
+	require.NoError(t, call("/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo"))
+
+	states <- state.OK
+	<-reports
+	<-reports
+
+	require.NoError(t, call("/greeter.Greeter/Greet"))
+}
+
+func TestGRPCInterceptor_Shed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	monitor, states := newControlledMonitor(ctx, t)
+
+	reports, unsubscribe := monitor.Subscribe()
+	defer unsubscribe()
+
+	interceptor := (&health.GRPCInterceptor{
+		Monitor: monitor,
+		Shed:    true,
+	}).Stream()
+
+	states <- state.Major
+	<-reports
+	<-reports
+
+	// with half of the HP remaining, roughly half of the requests are shed
+	shed := 0
+	for i := 0; i < 1000; i++ {
+		err := interceptor(nil, nil, &grpc.StreamServerInfo{FullMethod: "/greeter.Greeter/GreetMany"},
+			func(srv interface{}, stream grpc.ServerStream) error {
+				return nil
+			})
+
+		if status.Code(err) == codes.Unavailable {
+			shed++
+		}
+	}
+
+	require.Greater(t, shed, 300)
+	require.Less(t, shed, 700)
+}
@@ -1,29 +1,297 @@
 package health
 
 import (
+	"bytes"
+	"crypto/sha1"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"path"
+	"strconv"
+	"strings"
 
+	"github.com/mjpitz/go-gracefully/report"
 	"github.com/mjpitz/go-gracefully/state"
 )
 
+// Handler returns an http.Handler that serves the health report along with
+// endpoints for interacting with individual checks. Routes are resolved
+// relative to wherever the handler is mounted.
+//
+//	GET  /healthz                      - the full health report
+//	GET  /healthz/check/{name}         - the result of the named check
+//	GET  /healthz/checks?selector=a,b* - the results of the selected checks
+//	POST /healthz/check/{name}/run     - evaluates the named check immediately
+//
+// The status of the check endpoints is derived from the state of the named
+// check, or the worst state of the selected checks. A selector is a comma
+// separated list of names or patterns (as supported by path.Match), and every
+// check is returned when it's omitted. Unknown names result in a 404. When
+// authentication is configured, only authenticated callers may access the
+// check endpoints.
+func Handler(monitor *Monitor, opts ...HandlerOption) http.Handler {
+	options := newHandlerOptions(opts)
+	reportHandler := HandlerFunc(monitor, opts...)
+
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		segments := strings.Split(strings.Trim(request.URL.Path, "/"), "/")
+		n := len(segments)
+
+		isCheck := n >= 2 && segments[n-2] == "check" || segments[n-1] == "checks" ||
+			n >= 3 && segments[n-3] == "check" && segments[n-1] == "run"
+
+		if isCheck && !options.authenticated(request) {
+			options.unauthorized(writer)
+			return
+		}
+
+		switch {
+		case n >= 3 && segments[n-3] == "check" && segments[n-1] == "run":
+			triggerCheck(monitor, options, segments[n-2], writer, request)
+		case n >= 2 && segments[n-2] == "check":
+			checkResult(monitor, options, segments[n-1], writer, request)
+		case segments[n-1] == "checks":
+			checkResults(monitor, options, request.URL.Query().Get("selector"), writer, request)
+		default:
+			reportHandler(writer, request)
+		}
+	})
+}
+
 // HandlerFunc returns an http.HandlerFunc for users to register with their system.
-func HandlerFunc(monitor *Monitor) http.HandlerFunc {
+// The report format is negotiated using the Accept header or the "format"
+// query parameter (json, text, or html), and can be streamed as server-sent
+// events ("text/event-stream").
+func HandlerFunc(monitor *Monitor, opts ...HandlerOption) http.HandlerFunc {
+	options := newHandlerOptions(opts)
+
 	return func(writer http.ResponseWriter, request *http.Request) {
-		report := monitor.Report()
-		body, err := json.Marshal(report)
-		if err != nil {
-			writer.WriteHeader(http.StatusInternalServerError)
+		if strings.Contains(request.Header.Get("Accept"), eventStream) {
+			streamReports(monitor, options, writer, request)
 			return
 		}
 
-		writer.Header().Set("Content-Type", "application/json")
-		if report.State == state.Outage {
+		report, minimal := options.view(monitor, request)
+		format := negotiate(request)
+
+		body := &bytes.Buffer{}
+		if err := render(body, format, report, minimal); err != nil {
 			writer.WriteHeader(http.StatusInternalServerError)
-		} else {
-			writer.WriteHeader(http.StatusOK)
+			return
+		}
+
+		writer.Header().Set("Content-Type", contentTypes[format])
+		writer.Header().Set("Vary", "Accept, Authorization")
+
+		if options.caching {
+			etag := fmt.Sprintf(`"%x"`, sha1.Sum(body.Bytes()))
+			writer.Header().Set("ETag", etag)
+
+			if options.maxAge > 0 {
+				writer.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", int(options.maxAge.Seconds())))
+			} else {
+				writer.Header().Set("Cache-Control", "no-cache")
+			}
+
+			if matchesETag(request.Header.Get("If-None-Match"), etag) {
+				writer.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+
+		writer.WriteHeader(options.statusCode(report.State))
+
+		if request.Method != http.MethodHead {
+			_, _ = body.WriteTo(writer)
+		}
+	}
+}
+
+// matchesETag reports whether the If-None-Match header matches the etag.
+func matchesETag(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func checkResult(monitor *Monitor, options *handlerOptions, name string, writer http.ResponseWriter, request *http.Request) {
+	r, minimal := options.view(monitor, request)
+
+	result, ok := r.Results[name]
+	if !ok {
+		http.Error(writer, fmt.Sprintf("%s: %s", ErrUnknownCheck, name), http.StatusNotFound)
+		return
+	}
+
+	var body interface{} = result
+	if minimal {
+		body = minimalReport{State: result.LastCheck.State}
+	}
+
+	writeJSON(writer, request, options.statusCode(result.LastCheck.State), body)
+}
+
+func checkResults(monitor *Monitor, options *handlerOptions, selector string, writer http.ResponseWriter, request *http.Request) {
+	r, minimal := options.view(monitor, request)
+	all := r.Results
+	selected := make(map[string]report.CheckResult, len(all))
+
+	for _, term := range strings.Split(selector, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		if _, ok := all[term]; ok {
+			selected[term] = all[term]
+			continue
+		}
+
+		matched := false
+		for name, result := range all {
+			if ok, _ := path.Match(term, name); ok {
+				selected[name] = result
+				matched = true
+			}
 		}
 
+		// only patterns are allowed to match nothing
+		if !matched && !strings.ContainsAny(term, `*?[\`) {
+			http.Error(writer, fmt.Sprintf("%s: %s", ErrUnknownCheck, term), http.StatusNotFound)
+			return
+		}
+	}
+
+	if selector == "" {
+		selected = all
+	}
+
+	states := make([]state.State, 0, len(selected))
+	minimalResults := make(map[string]minimalReport, len(selected))
+	for name, result := range selected {
+		states = append(states, result.LastCheck.State)
+		minimalResults[name] = minimalReport{State: result.LastCheck.State}
+	}
+
+	var body interface{} = selected
+	if minimal {
+		body = minimalResults
+	}
+
+	writeJSON(writer, request, options.statusCode(state.Worst(states...)), body)
+}
+
+func writeJSON(writer http.ResponseWriter, request *http.Request, status int, value interface{}) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		writer.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+
+	if request.Method != http.MethodHead {
 		_, _ = writer.Write(body)
 	}
 }
+
+func triggerCheck(monitor *Monitor, options *handlerOptions, name string, writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		writer.Header().Set("Allow", http.MethodPost)
+		writer.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	results, wait, err := monitor.trigger(request.Context(), []string{name})
+	switch {
+	case errors.Is(err, ErrUnknownCheck):
+		http.Error(writer, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, ErrNotTriggerable):
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, ErrRateLimited):
+		writer.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		http.Error(writer, err.Error(), http.StatusTooManyRequests)
+		return
+	case err != nil:
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := results[name]
+
+	var body interface{} = result
+	if options.minimal || !options.authenticated(request) {
+		body = minimalReport{State: result.State}
+	} else if options.redact != nil {
+		body = redactResult(result, options.redact)
+	}
+
+	writeJSON(writer, request, http.StatusOK, body)
+}
+
+const eventStream = "text/event-stream"
+
+// streamReports sends the report as a server-sent event each time the health of
+// a check or the system changes.
+func streamReports(monitor *Monitor, options *handlerOptions, writer http.ResponseWriter, request *http.Request) {
+	flusher, ok := writer.(http.Flusher)
+	if !ok {
+		http.Error(writer, "streaming not supported", http.StatusNotAcceptable)
+		return
+	}
+
+	minimal := options.minimal || !options.authenticated(request)
+
+	reports, unsubscribe := monitor.Subscribe()
+	defer unsubscribe()
+
+	// drain reports on a separate go routine so that a slow client never
+	// blocks the monitor. changes are coalesced into a single notification.
+	changed := make(chan struct{}, 1)
+	changed <- struct{}{}
+
+	go func() {
+		for range reports {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	writer.Header().Set("Content-Type", eventStream)
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.WriteHeader(http.StatusOK)
+
+	stopCh := request.Context().Done()
+	for {
+		select {
+		case <-changed:
+			report := monitor.Report()
+			if options.redact != nil && !minimal {
+				report = redactReport(report, options.redact)
+			}
+
+			body, err := json.Marshal(payload(report, minimal))
+			if err != nil {
+				return
+			}
+
+			if _, err := fmt.Fprintf(writer, "event: report\ndata: %s\n\n", body); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-stopCh:
+			return
+		}
+	}
+}
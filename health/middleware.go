@@ -0,0 +1,102 @@
+package health
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mjpitz/go-gracefully/state"
+)
+
+// StateHeader is the response header used by Middleware to report the
+// state that was used to admit the request.
+const StateHeader = "X-Health-State"
+
+// RoutePolicy describes how requests for a route are admitted. A policy
+// matches requests whose path starts with the Prefix and, when provided, whose
+// method is one of the Methods. Requests are admitted based on the worst state
+// of the Checks, or of the system when none are provided. Requests are
+// rejected when any of the Checks is not registered. Exempt routes are always
+// admitted.
+type RoutePolicy struct {
+	Prefix  string
+	Methods []string
+	Checks  []string
+	Exempt  bool
+}
+
+// Middleware rejects HTTP requests based on the health reported by a Monitor.
+// Requests are rejected with a 503 while in an Outage, along with a Retry-After
+// header asking callers to wait RetryAfter (defaulting to DefaultRetryAfter).
+// When Shed is set, requests are also rejected with a probability proportional
+// to the missing HP while in a Major state. The state used to admit each
+// request is reported in the X-Health-State header.
+//
+// The first of the Routes that matches a request determines how it's admitted.
+// Requests that match no route are admitted based on the system state. For
+// example, health endpoints can be exempt and writes can be rejected while the
+// database is down, even though reads are still served.
+type Middleware struct {
+	Monitor    *Monitor
+	Routes     []RoutePolicy
+	RetryAfter time.Duration
+	Shed       bool
+}
+
+// Handler wraps the provided handler.
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		policy := m.route(request)
+
+		if policy.Exempt {
+			system, _ := m.Monitor.summary.result("")
+			writer.Header().Set(StateHeader, string(system.State))
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		shedFrom := state.State("")
+		if m.Shed {
+			shedFrom = state.Major
+		}
+
+		current, err := m.Monitor.admit(policy.Checks, shedFrom)
+		writer.Header().Set(StateHeader, string(current))
+
+		if err != nil {
+			retryAfter := m.RetryAfter
+			if retryAfter <= 0 {
+				retryAfter = DefaultRetryAfter
+			}
+
+			writer.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			http.Error(writer, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		next.ServeHTTP(writer, request)
+	})
+}
+
+// route returns the first policy matching the request.
+func (m *Middleware) route(request *http.Request) RoutePolicy {
+	for _, policy := range m.Routes {
+		if !strings.HasPrefix(request.URL.Path, policy.Prefix) {
+			continue
+		}
+
+		if len(policy.Methods) == 0 {
+			return policy
+		}
+
+		for _, method := range policy.Methods {
+			if strings.EqualFold(method, request.Method) {
+				return policy
+			}
+		}
+	}
+
+	return RoutePolicy{}
+}
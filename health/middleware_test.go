@@ -0,0 +1,236 @@
+package health_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/health"
+	"github.com/mjpitz/go-gracefully/state"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	database := make(chan state.State)
+	cache := make(chan state.State)
+
+	controlled := func(name string, states chan state.State) check.Check {
+		return &check.Stream{
+			Metadata: check.Metadata{
+				Name:   name,
+				Weight: 10,
+			},
+			WatchFunc: func(ctx context.Context, channel chan check.Result) {
+				for {
+					select {
+					case s := <-states:
+						channel <- check.Result{State: s}
+					case <-ctx.Done():
+						return
+					}
+				}
+			},
+		}
+	}
+
+	monitor := health.NewMonitor(controlled("database", database), controlled("cache", cache))
+
+	reports, unsubscribe := monitor.Subscribe()
+	defer unsubscribe()
+
+	require.NoError(t, monitor.Start(ctx))
+
+	middleware := &health.Middleware{
+		Monitor: monitor,
+		Routes: []health.RoutePolicy{
+			{Prefix: "/healthz", Exempt: true},
+			{Prefix: "/", Methods: []string{http.MethodPost}, Checks: []string{"database"}},
+			{Prefix: "/", Checks: []string{"cache"}},
+		},
+	}
+
+	handler := middleware.Handler(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusNoContent)
+	}))
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+		return recorder
+	}
+
+	database <- state.Outage
+	<-reports
+	<-reports
+
+	cache <- state.OK
+	<-reports
+	<-reports
+
+	{
+		recorder := serve(http.MethodPost, "/items")
+		require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+		require.Equal(t, "30", recorder.Header().Get("Retry-After"))
+		require.Equal(t, "outage", recorder.Header().Get(health.StateHeader))
+	}
+
+	{
+		recorder := serve(http.MethodGet, "/items")
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+		require.Equal(t, "ok", recorder.Header().Get(health.StateHeader))
+	}
+
+	{
+		recorder := serve(http.MethodPost, "/healthz")
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+		require.Equal(t, "minor", recorder.Header().Get(health.StateHeader))
+	}
+}
+
+func TestMiddleware_Shed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	monitor, states := newControlledMonitor(ctx, t)
+
+	reports, unsubscribe := monitor.Subscribe()
+	defer unsubscribe()
+
+	handler := (&health.Middleware{
+		Monitor: monitor,
+		Shed:    true,
+	}).Handler(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {}))
+
+	shed := func() int {
+		count := 0
+		for i := 0; i < 1000; i++ {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if recorder.Code == http.StatusServiceUnavailable {
+				count++
+			}
+		}
+		return count
+	}
+
+	// load is only shed once the system is in a major state
+	states <- state.Minor
+	<-reports
+	<-reports
+	require.Equal(t, 0, shed())
+
+	states <- state.Major
+	<-reports
+	<-reports
+
+	count := shed()
+	require.Greater(t, count, 300)
+	require.Less(t, count, 700)
+}
+
+func TestMiddleware_Startup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	database := make(chan state.State)
+
+	pending := func(name string) check.Check {
+		return &check.Stream{
+			Metadata: check.Metadata{
+				Name:   name,
+				Weight: 10,
+			},
+			WatchFunc: func(ctx context.Context, channel chan check.Result) {
+				<-ctx.Done()
+			},
+		}
+	}
+
+	monitor := health.NewMonitor(
+		&check.Stream{
+			Metadata: check.Metadata{
+				Name:   "database",
+				Weight: 10,
+			},
+			WatchFunc: func(ctx context.Context, channel chan check.Result) {
+				for {
+					select {
+					case s := <-database:
+						channel <- check.Result{State: s}
+					case <-ctx.Done():
+						return
+					}
+				}
+			},
+		},
+		pending("cache"),
+		pending("queue"),
+	)
+
+	reports, unsubscribe := monitor.Subscribe()
+	defer unsubscribe()
+
+	require.NoError(t, monitor.Start(ctx))
+
+	handler := (&health.Middleware{
+		Monitor: monitor,
+		Shed:    true,
+	}).Handler(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusNoContent)
+	}))
+
+	// checks that have yet to report don't count against the system
+	database <- state.Minor
+	<-reports
+	<-reports
+	require.Equal(t, state.Outage, monitor.Report().State)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusNoContent, recorder.Code)
+	require.Equal(t, "minor", recorder.Header().Get(health.StateHeader))
+}
+
+func TestMiddleware_Unknown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	monitor, states := newControlledMonitor(ctx, t)
+
+	reports, unsubscribe := monitor.Subscribe()
+	defer unsubscribe()
+
+	handler := (&health.Middleware{
+		Monitor: monitor,
+		Routes: []health.RoutePolicy{
+			{Prefix: "/missing", Checks: []string{"missing"}},
+		},
+	}).Handler(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusNoContent)
+	}))
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+
+	// checks that are misconfigured are rejected
+	require.Equal(t, http.StatusServiceUnavailable, serve("/missing").Code)
+
+	// a check that timed out has reported, so it counts against the system
+	states <- state.Unknown
+	<-reports
+	<-reports
+
+	recorder := serve("/")
+	require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	require.Equal(t, "outage", recorder.Header().Get(health.StateHeader))
+}
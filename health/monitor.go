@@ -2,7 +2,9 @@ package health
 
 import (
 	"context"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/jonboulle/clockwork"
 
@@ -11,6 +13,10 @@ import (
 	"github.com/mjpitz/go-gracefully/state"
 )
 
+// DefaultTriggerInterval is the minimum amount of time that must pass between
+// two on demand evaluations of the same check.
+const DefaultTriggerInterval = 5 * time.Second
+
 // NewMonitor constructs and returns a monitor capable of observing the
 // provided set of checks. Monitors must be started and can be observed.
 func NewMonitor(checks ...check.Check) *Monitor {
@@ -25,21 +31,25 @@ func NewMonitor(checks ...check.Check) *Monitor {
 	}
 
 	return &Monitor{
-		clock:   clock,
-		mu:      &sync.Mutex{},
-		started: false,
+		clock:           clock,
+		mu:              &sync.Mutex{},
+		started:         false,
+		triggerInterval: DefaultTriggerInterval,
+		lastTriggered:   make(map[string]time.Time),
 		summary: &summary{
-			clock:       clock,
-			mu:          &sync.Mutex{},
-			checks:      checkIndex,
-			subscribers: make(map[string]chan check.Report),
-			totalHP:     totalHP,
-			hp:          0,
+			clock:         clock,
+			mu:            &sync.Mutex{},
+			subscribersMu: &sync.Mutex{},
+			checks:        checkIndex,
+			subscribers:   make(map[string]chan check.Report),
+			totalHP:       totalHP,
+			hp:            0,
 			system: &check.Result{
 				State: state.Unknown,
 			},
 			lastResults:      make(map[string]*check.Result),
 			lastKnownResults: make(map[string]*check.Result),
+			panics:           make(map[string]uint64),
 		},
 	}
 }
@@ -50,9 +60,12 @@ func NewMonitor(checks ...check.Check) *Monitor {
 type Monitor struct {
 	clock clockwork.Clock
 
-	mu      *sync.Mutex
-	started bool
-	summary *summary
+	mu              *sync.Mutex
+	started         bool
+	workers         int
+	triggerInterval time.Duration
+	lastTriggered   map[string]time.Time
+	summary         *summary
 }
 
 // SetClock updates the internal clock used by the system. This must be called
@@ -71,6 +84,31 @@ func (m *Monitor) SetClock(clock clockwork.Clock) error {
 	return nil
 }
 
+// SetWorkers configures the Monitor to drive all Periodic checks from a shared
+// check.Scheduler with the provided number of workers instead of a go routine
+// per check. This must be called before the system is started.
+func (m *Monitor) SetWorkers(workers int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.started {
+		return ErrAlreadyStarted
+	}
+
+	m.workers = workers
+
+	return nil
+}
+
+// SetTriggerInterval updates the minimum amount of time that must pass between
+// two on demand evaluations of the same check.
+func (m *Monitor) SetTriggerInterval(interval time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.triggerInterval = interval
+}
+
 // Start initiates all check watches.
 func (m *Monitor) Start(ctx context.Context) error {
 	m.mu.Lock()
@@ -86,10 +124,24 @@ func (m *Monitor) Start(ctx context.Context) error {
 		// +1 for the system
 		reports := make(chan check.Report, len(m.summary.checks)+1)
 
+		var scheduler *check.Scheduler
+		if m.workers > 0 {
+			scheduler = check.NewScheduler(m.clock, m.workers)
+		}
+
 		for _, registered := range m.summary.checks {
+			if periodic, ok := registered.(*check.Periodic); ok && scheduler != nil {
+				scheduler.Schedule(periodic, reports)
+				continue
+			}
+
 			registered.Watch(ctx, reports)
 		}
 
+		if scheduler != nil {
+			scheduler.Start(ctx)
+		}
+
 		stopCh := ctx.Done()
 		for {
 			select {
@@ -114,3 +166,89 @@ func (m *Monitor) Subscribe() (chan check.Report, UnsubFunc) {
 func (m *Monitor) Report() report.Report {
 	return m.summary.report()
 }
+
+// Trigger forces an immediate evaluation of the named checks and returns their
+// fresh results. When no names are provided, every triggerable check is
+// evaluated. A check may only be triggered once per trigger interval.
+func (m *Monitor) Trigger(ctx context.Context, names ...string) (map[string]check.Result, error) {
+	results, _, err := m.trigger(ctx, names)
+	return results, err
+}
+
+// trigger evaluates the named checks as described by Trigger. When rate
+// limited, it also returns how long the caller must wait before trying again.
+func (m *Monitor) trigger(ctx context.Context, names []string) (map[string]check.Result, time.Duration, error) {
+	triggerables, wait, err := m.reserve(names)
+	if err != nil {
+		return nil, wait, err
+	}
+
+	mu := &sync.Mutex{}
+	wg := &sync.WaitGroup{}
+	results := make(map[string]check.Result, len(triggerables))
+
+	for name, triggerable := range triggerables {
+		wg.Add(1)
+
+		go func(name string, triggerable check.Triggerable) {
+			defer wg.Done()
+
+			result := triggerable.Trigger(ctx)
+			m.summary.update(check.Report{
+				Check:  triggerable,
+				Result: result,
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[name] = result
+		}(name, triggerable)
+	}
+
+	wg.Wait()
+
+	return results, 0, nil
+}
+
+// reserve resolves the named checks and records the time they were triggered.
+// No check is reserved unless all of them can be triggered. When a check was
+// triggered too recently, the remaining wait is returned with ErrRateLimited.
+func (m *Monitor) reserve(names []string) (map[string]check.Triggerable, time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(names) == 0 {
+		for name, registered := range m.summary.checks {
+			if _, ok := registered.(check.Triggerable); ok {
+				names = append(names, name)
+			}
+		}
+	}
+
+	now := m.clock.Now()
+	triggerables := make(map[string]check.Triggerable, len(names))
+
+	for _, name := range names {
+		registered, ok := m.summary.checks[name]
+		if !ok {
+			return nil, 0, fmt.Errorf("%w: %s", ErrUnknownCheck, name)
+		}
+
+		triggerable, ok := registered.(check.Triggerable)
+		if !ok {
+			return nil, 0, fmt.Errorf("%w: %s", ErrNotTriggerable, name)
+		}
+
+		if last, ok := m.lastTriggered[name]; ok && now.Sub(last) < m.triggerInterval {
+			return nil, m.triggerInterval - now.Sub(last), fmt.Errorf("%w: %s", ErrRateLimited, name)
+		}
+
+		triggerables[name] = triggerable
+	}
+
+	for name := range triggerables {
+		m.lastTriggered[name] = now
+	}
+
+	return triggerables, 0, nil
+}
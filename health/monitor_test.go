@@ -0,0 +1,68 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/health"
+	"github.com/mjpitz/go-gracefully/state"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTriggerMonitor(clock clockwork.Clock) *health.Monitor {
+	monitor := health.NewMonitor(
+		&check.Periodic{
+			Metadata: check.Metadata{
+				Name:   "periodic",
+				Weight: 10,
+			},
+			Interval: time.Hour,
+			Timeout:  time.Second,
+			Clock:    clock,
+			RunFunc: func(ctx context.Context) (state.State, error) {
+				return state.OK, nil
+			},
+		},
+		&check.Stream{
+			Metadata: check.Metadata{
+				Name:   "stream",
+				Weight: 10,
+			},
+			WatchFunc: func(ctx context.Context, channel chan check.Result) {},
+		},
+	)
+
+	_ = monitor.SetClock(clock)
+	return monitor
+}
+
+func TestMonitor_Trigger(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	monitor := newTriggerMonitor(clock)
+
+	results, err := monitor.Trigger(context.TODO(), "periodic")
+	require.NoError(t, err)
+	require.Equal(t, state.OK, results["periodic"].State)
+	require.Equal(t, state.OK, monitor.Report().Results["periodic"].LastCheck.State)
+
+	_, err = monitor.Trigger(context.TODO(), "periodic")
+	require.True(t, errors.Is(err, health.ErrRateLimited))
+
+	clock.Advance(health.DefaultTriggerInterval)
+
+	results, err = monitor.Trigger(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	_, err = monitor.Trigger(context.TODO(), "stream")
+	require.True(t, errors.Is(err, health.ErrNotTriggerable))
+
+	_, err = monitor.Trigger(context.TODO(), "missing")
+	require.True(t, errors.Is(err, health.ErrUnknownCheck))
+}
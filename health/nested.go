@@ -0,0 +1,71 @@
+package health
+
+import (
+	"context"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/report"
+	"github.com/mjpitz/go-gracefully/state"
+)
+
+// Nested wraps a Monitor so that it can be registered as a check with another
+// Monitor. This allows large systems to be structured as sub-systems, each
+// with their own set of checks. The system result of the nested Monitor is
+// reported as the result of the check, and its full report is nested within
+// the report of the parent Monitor. The nested Monitor is started when the
+// check is watched, if it has not been started already.
+type Nested struct {
+	check.Metadata
+	Monitor *Monitor `json:"-"`
+}
+
+// GetMetadata returns meta information about the check.
+func (n *Nested) GetMetadata() check.Metadata {
+	return n.Metadata
+}
+
+// Watch observes changes in the system health of the nested Monitor.
+func (n *Nested) Watch(ctx context.Context, channel chan check.Report) {
+	reports, unsubscribe := n.Monitor.Subscribe()
+
+	// the only error indicates the monitor is already running
+	_ = n.Monitor.Start(ctx)
+
+	go func() {
+		defer unsubscribe()
+
+		// the nested monitor may have been evaluated before it was watched
+		if current := n.Monitor.Report().Result; current.State != state.Unknown {
+			channel <- check.Report{
+				Check:  n,
+				Result: current,
+			}
+		}
+
+		stopCh := ctx.Done()
+		for {
+			select {
+			case r := <-reports:
+				// reports without a check represent the system
+				if r.Check != nil {
+					continue
+				}
+
+				channel <- check.Report{
+					Check:  n,
+					Result: r.Result,
+				}
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Report returns a summary of the nested systems health.
+func (n *Nested) Report() report.Report {
+	return n.Monitor.Report()
+}
+
+var _ check.Check = &Nested{}
+var _ report.Reporter = &Nested{}
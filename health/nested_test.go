@@ -0,0 +1,51 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/health"
+	"github.com/mjpitz/go-gracefully/report"
+	"github.com/mjpitz/go-gracefully/state"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNested(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	storage, states := newControlledMonitor(ctx, t)
+
+	monitor := health.NewMonitor(&health.Nested{
+		Metadata: check.Metadata{
+			Name:   "storage",
+			Weight: 10,
+		},
+		Monitor: storage,
+	})
+
+	reports, unsubscribe := monitor.Subscribe()
+	defer unsubscribe()
+
+	require.NoError(t, monitor.Start(ctx))
+
+	states <- state.Major
+
+	// one report for the nested check, one for the system
+	require.Equal(t, state.Major, (<-reports).Result.State)
+	require.Equal(t, state.Major, (<-reports).Result.State)
+
+	data, err := json.Marshal(monitor.Report())
+	require.NoError(t, err)
+
+	decoded := report.Report{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	nested := decoded.Results["storage"].Report
+	require.NotNil(t, nested)
+	require.Equal(t, state.Major, nested.State)
+	require.Equal(t, state.Major, nested.Results["controlled"].LastCheck.State)
+}
@@ -0,0 +1,101 @@
+package health
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mjpitz/go-gracefully/report"
+	"github.com/mjpitz/go-gracefully/state"
+)
+
+// HandlerOption customizes the responses of the health handlers.
+type HandlerOption func(options *handlerOptions)
+
+type handlerOptions struct {
+	statusCodes    map[state.State]int
+	unknownFails   bool
+	caching        bool
+	maxAge         time.Duration
+	minimal        bool
+	authenticators []Authenticator
+	challenge      string
+	redact         Redactor
+}
+
+func newHandlerOptions(opts []HandlerOption) *handlerOptions {
+	options := &handlerOptions{
+		statusCodes: map[state.State]int{
+			state.Outage: http.StatusInternalServerError,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return options
+}
+
+// view returns the report the caller of the request may see, and whether it
+// must be limited to the state of the system.
+func (o *handlerOptions) view(monitor *Monitor, request *http.Request) (report.Report, bool) {
+	minimal := o.minimal || !o.authenticated(request)
+
+	r := monitor.Report()
+	if o.redact != nil && !minimal {
+		r = redactReport(r, o.redact)
+	}
+
+	return r, minimal
+}
+
+// statusCode returns the HTTP status code for the provided state.
+func (o *handlerOptions) statusCode(current state.State) int {
+	if current == state.Unknown && o.unknownFails {
+		current = state.Outage
+	}
+
+	if code, ok := o.statusCodes[current]; ok {
+		return code
+	}
+
+	return http.StatusOK
+}
+
+// WithStatusCodes overrides the HTTP status code returned for each of the
+// provided states. By default, an Outage returns a 500 and every other state a
+// 200. For example, an Outage can be mapped to a 503 instead.
+func WithStatusCodes(codes map[state.State]int) HandlerOption {
+	return func(options *handlerOptions) {
+		for current, code := range codes {
+			options.statusCodes[current] = code
+		}
+	}
+}
+
+// WithUnknownFailing treats an Unknown state like an Outage when determining
+// the HTTP status code (e.g. while the checks have yet to be evaluated).
+func WithUnknownFailing() HandlerOption {
+	return func(options *handlerOptions) {
+		options.unknownFails = true
+	}
+}
+
+// WithCaching sets an ETag derived from the report, responding with a 304 when
+// it matches the If-None-Match header of the request, and a Cache-Control header
+// allowing clients to cache the report for maxAge. A maxAge of zero requires
+// clients to revalidate each time.
+func WithCaching(maxAge time.Duration) HandlerOption {
+	return func(options *handlerOptions) {
+		options.caching = true
+		options.maxAge = maxAge
+	}
+}
+
+// WithMinimal only exposes the state of the system, omitting the results of
+// every check. This is useful when the endpoint is publicly accessible.
+func WithMinimal() HandlerOption {
+	return func(options *handlerOptions) {
+		options.minimal = true
+	}
+}
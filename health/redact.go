@@ -0,0 +1,95 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/report"
+)
+
+// Redacted replaces sensitive values scrubbed by RedactPatterns.
+const Redacted = "[REDACTED]"
+
+// Redactor scrubs sensitive information from a string.
+type Redactor = func(value string) string
+
+// WithRedactor applies the Redactor to every error message and string within
+// the details of a result before it's served.
+func WithRedactor(redactor Redactor) HandlerOption {
+	return func(options *handlerOptions) {
+		options.redact = redactor
+	}
+}
+
+// RedactPatterns returns a Redactor that replaces all matches of the patterns
+// (e.g. connection strings or IP addresses) with Redacted.
+func RedactPatterns(patterns ...*regexp.Regexp) Redactor {
+	return func(value string) string {
+		for _, pattern := range patterns {
+			value = pattern.ReplaceAllString(value, Redacted)
+		}
+		return value
+	}
+}
+
+func redactReport(r report.Report, redact Redactor) report.Report {
+	redacted := report.Report{
+		Result:  redactResult(r.Result, redact),
+		Results: make(map[string]report.CheckResult, len(r.Results)),
+	}
+
+	for name, result := range r.Results {
+		result.LastCheck = redactResult(result.LastCheck, redact)
+		result.LastKnownCheck = redactResult(result.LastKnownCheck, redact)
+
+		if result.Report != nil {
+			nested := redactReport(*result.Report, redact)
+			result.Report = &nested
+		}
+
+		redacted.Results[name] = result
+	}
+
+	return redacted
+}
+
+func redactResult(result check.Result, redact Redactor) check.Result {
+	message, ok := errorMessage(result.Error)
+
+	result.Error = nil
+	if ok {
+		result.Error = check.WrapError(errors.New(redact(message)))
+	}
+
+	if len(result.Details) > 0 {
+		// details can hold any value, so they're normalized through JSON
+		details := make(map[string]interface{}, len(result.Details))
+		if data, err := json.Marshal(result.Details); err == nil {
+			_ = json.Unmarshal(data, &details)
+		}
+
+		result.Details = redactValue(details, redact).(map[string]interface{})
+	}
+
+	return result
+}
+
+func redactValue(value interface{}, redact Redactor) interface{} {
+	switch v := value.(type) {
+	case string:
+		return redact(v)
+	case map[string]interface{}:
+		for key, nested := range v {
+			v[key] = redactValue(nested, redact)
+		}
+		return v
+	case []interface{}:
+		for i, nested := range v {
+			v[i] = redactValue(nested, redact)
+		}
+		return v
+	}
+	return value
+}
@@ -0,0 +1,137 @@
+package health
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/report"
+	"github.com/mjpitz/go-gracefully/state"
+)
+
+// Remote evaluates another service that exposes its health using HandlerFunc.
+// The state of the remote system is used as the result, and the results of
+// each remote check are included in the details. This allows a Monitor to
+// aggregate the health of many services.
+type Remote struct {
+	URL    string       `json:"url"`
+	Client *http.Client `json:"-"`
+}
+
+// Run fetches the remote report once.
+func (r *Remote) Run(ctx context.Context) check.Result {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, r.URL, nil)
+	if err != nil {
+		return r.failed(err)
+	}
+	request.Header.Set("Accept", "application/json")
+
+	response, err := r.client().Do(request)
+	if err != nil {
+		return r.failed(err)
+	}
+	defer response.Body.Close()
+
+	// the status code reflects the remote state, so always attempt to decode
+	remote := report.Report{}
+	if err := json.NewDecoder(response.Body).Decode(&remote); err != nil {
+		return r.failed(fmt.Errorf("failed to decode report (status %d): %w", response.StatusCode, err))
+	}
+
+	return r.result(remote)
+}
+
+// Watch consumes the server-sent events published by the remote endpoint,
+// reporting a result for each event. It returns once the stream ends. Pass it
+// to a Stream check in order to follow the remote system without polling.
+func (r *Remote) Watch(ctx context.Context, channel chan check.Result) {
+	send := func(result check.Result) {
+		select {
+		case channel <- result:
+		case <-ctx.Done():
+		}
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, r.URL, nil)
+	if err != nil {
+		send(r.failed(err))
+		return
+	}
+	request.Header.Set("Accept", eventStream)
+
+	response, err := r.client().Do(request)
+	if err != nil {
+		send(r.failed(err))
+		return
+	}
+	defer response.Body.Close()
+
+	if !strings.HasPrefix(response.Header.Get("Content-Type"), eventStream) {
+		send(r.failed(fmt.Errorf("unexpected response (status %d) from %s", response.StatusCode, r.URL)))
+		return
+	}
+
+	data := &bytes.Buffer{}
+	scanner := bufio.NewScanner(response.Body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, "data:"):
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		case line == "" && data.Len() > 0:
+			remote := report.Report{}
+			if err := json.Unmarshal(data.Bytes(), &remote); err != nil {
+				send(r.failed(fmt.Errorf("failed to decode report: %w", err)))
+			} else {
+				send(r.result(remote))
+			}
+			data.Reset()
+		}
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	err = scanner.Err()
+	if err == nil {
+		err = fmt.Errorf("stream from %s closed", r.URL)
+	}
+	send(r.failed(err))
+}
+
+func (r *Remote) client() *http.Client {
+	if r.Client == nil {
+		return http.DefaultClient
+	}
+	return r.Client
+}
+
+func (r *Remote) result(remote report.Report) check.Result {
+	return check.Result{
+		State: remote.State,
+		Details: map[string]interface{}{
+			"url":       r.URL,
+			"currentHP": remote.CurrentHP,
+			"results":   remote.Results,
+		},
+	}
+}
+
+func (r *Remote) failed(err error) check.Result {
+	return check.Result{
+		State: state.Outage,
+		Error: check.WrapError(err),
+		Details: map[string]interface{}{
+			"url": r.URL,
+		},
+	}
+}
@@ -0,0 +1,94 @@
+package health_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/health"
+	"github.com/mjpitz/go-gracefully/report"
+	"github.com/mjpitz/go-gracefully/state"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newControlledMonitor returns a started monitor with a single check whose
+// state is controlled by the returned channel.
+func newControlledMonitor(ctx context.Context, t *testing.T) (*health.Monitor, chan state.State) {
+	states := make(chan state.State)
+
+	monitor := health.NewMonitor(&check.Stream{
+		Metadata: check.Metadata{
+			Name:   "controlled",
+			Weight: 10,
+		},
+		WatchFunc: func(ctx context.Context, channel chan check.Result) {
+			for {
+				select {
+				case s := <-states:
+					channel <- check.Result{State: s}
+				case <-ctx.Done():
+					return
+				}
+			}
+		},
+	})
+
+	require.NoError(t, monitor.Start(ctx))
+	return monitor, states
+}
+
+func TestRemote(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	monitor, states := newControlledMonitor(ctx, t)
+
+	reports, unsubscribe := monitor.Subscribe()
+	defer unsubscribe()
+
+	server := httptest.NewServer(health.HandlerFunc(monitor))
+	defer server.Close()
+
+	remote := &health.Remote{URL: server.URL}
+
+	states <- state.Outage
+	<-reports
+	<-reports
+
+	{
+		result := remote.Run(ctx)
+		require.Equal(t, state.Outage, result.State)
+		require.Nil(t, result.Error)
+
+		results := result.Details["results"].(map[string]report.CheckResult)
+		require.Equal(t, state.Outage, results["controlled"].LastCheck.State)
+	}
+
+	watchCtx, stopWatch := context.WithCancel(ctx)
+	results := make(chan check.Result, 1)
+	go remote.Watch(watchCtx, results)
+
+	require.Equal(t, state.Outage, (<-results).State)
+
+	states <- state.OK
+	<-reports
+	<-reports
+
+	for result := range results {
+		// changes to the check and the system may be coalesced
+		if result.State == state.OK {
+			break
+		}
+	}
+
+	stopWatch()
+	server.Close()
+
+	{
+		result := remote.Run(ctx)
+		require.Equal(t, state.Outage, result.State)
+		require.NotNil(t, result.Error)
+	}
+}
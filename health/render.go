@@ -0,0 +1,226 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/report"
+	"github.com/mjpitz/go-gracefully/state"
+)
+
+const (
+	formatJSON = "json"
+	formatText = "text"
+	formatHTML = "html"
+)
+
+// negotiate determines the format of the response from the "format" query
+// parameter, falling back to the Accept header. JSON is the default.
+func negotiate(request *http.Request) string {
+	switch format := request.URL.Query().Get("format"); format {
+	case formatJSON, formatText, formatHTML:
+		return format
+	}
+
+	for _, accepted := range strings.Split(request.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil {
+			continue
+		}
+
+		switch mediaType {
+		case "application/json":
+			return formatJSON
+		case "text/plain":
+			return formatText
+		case "text/html":
+			return formatHTML
+		}
+	}
+
+	return formatJSON
+}
+
+// contentTypes maps each format onto the content type of the response.
+var contentTypes = map[string]string{
+	formatJSON: "application/json",
+	formatText: "text/plain; charset=utf-8",
+	formatHTML: "text/html; charset=utf-8",
+}
+
+// minimalReport only exposes the state of the system.
+type minimalReport struct {
+	State state.State `json:"state"`
+}
+
+// payload returns the representation of the report that is serialized.
+func payload(r report.Report, minimal bool) interface{} {
+	if minimal {
+		return minimalReport{State: r.State}
+	}
+	return r
+}
+
+// render writes the report using the provided format. When minimal, only the
+// state of the system is written.
+func render(writer io.Writer, format string, r report.Report, minimal bool) error {
+	switch format {
+	case formatText:
+		if minimal {
+			_, err := fmt.Fprintln(writer, r.State)
+			return err
+		}
+		return renderText(writer, r)
+	case formatHTML:
+		return renderHTML(writer, r, minimal)
+	default:
+		return json.NewEncoder(writer).Encode(payload(r, minimal))
+	}
+}
+
+// row is a single check within a rendered report. Checks of nested reports are
+// prefixed with the name of their parent.
+type row struct {
+	Name      string
+	State     state.State
+	Weight    uint
+	LastCheck string
+	Error     string
+	Runbook   string
+}
+
+func rows(prefix string, r report.Report) []row {
+	names := make([]string, 0, len(r.Results))
+	for name := range r.Results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	all := make([]row, 0, len(names))
+	for _, name := range names {
+		result := r.Results[name]
+
+		current := row{
+			Name:      prefix + name,
+			State:     result.LastCheck.State,
+			Weight:    result.Weight,
+			LastCheck: "-",
+			Error:     "-",
+			Runbook:   result.Runbook,
+		}
+
+		if !result.LastCheck.Timestamp.IsZero() {
+			current.LastCheck = result.LastCheck.Timestamp.UTC().Format(time.RFC3339)
+		}
+
+		if message, ok := errorMessage(result.LastCheck.Error); ok {
+			current.Error = message
+		}
+
+		all = append(all, current)
+
+		if result.Report != nil {
+			all = append(all, rows(current.Name+"/", *result.Report)...)
+		}
+	}
+
+	return all
+}
+
+// errorMessage returns the message of the error, if present. Checks may report
+// a nil *check.Error, which is a non-nil error.
+func errorMessage(err error) (string, bool) {
+	if wrapped, ok := err.(*check.Error); err == nil || ok && wrapped == nil {
+		return "", false
+	}
+	return err.Error(), true
+}
+
+// renderText writes the report as a plain-text table.
+func renderText(writer io.Writer, r report.Report) error {
+	table := tabwriter.NewWriter(writer, 0, 4, 2, ' ', 0)
+
+	_, _ = fmt.Fprintf(table, "SYSTEM\t%s\tHP %.2f\n\n", r.State, r.CurrentHP)
+	_, _ = fmt.Fprintln(table, "NAME\tSTATE\tWEIGHT\tLAST CHECK\tERROR\tRUNBOOK")
+
+	for _, current := range rows("", r) {
+		runbook := current.Runbook
+		if runbook == "" {
+			runbook = "-"
+		}
+
+		_, _ = fmt.Fprintf(table, "%s\t%s\t%d\t%s\t%s\t%s\n",
+			current.Name, current.State, current.Weight, current.LastCheck,
+			strings.ReplaceAll(current.Error, "\n", " "), runbook)
+	}
+
+	return table.Flush()
+}
+
+var statusPage = template.Must(template.New("status").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Health: {{ .State }}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #ddd; padding: 0.5em; text-align: left; vertical-align: top; }
+.state { font-weight: bold; text-transform: uppercase; }
+.ok { color: #1a7f37; }
+.minor { color: #9a6700; }
+.major { color: #bc4c00; }
+.outage { color: #cf222e; }
+.unknown { color: #6e7781; }
+</style>
+</head>
+<body>
+<h1>System: <span class="state {{ .State }}">{{ .State }}</span></h1>
+{{- if not .Minimal }}
+<p>HP: {{ printf "%.2f" .CurrentHP }}</p>
+<table>
+<tr><th>Name</th><th>State</th><th>Weight</th><th>Last check</th><th>Error</th><th>Runbook</th></tr>
+{{- range .Rows }}
+<tr>
+<td>{{ .Name }}</td>
+<td class="state {{ .State }}">{{ .State }}</td>
+<td>{{ .Weight }}</td>
+<td>{{ .LastCheck }}</td>
+<td>{{ .Error }}</td>
+<td>{{ if .Runbook }}<a href="{{ .Runbook }}">runbook</a>{{ else }}-{{ end }}</td>
+</tr>
+{{- end }}
+</table>
+{{- end }}
+</body>
+</html>
+`))
+
+// renderHTML writes the report as a self-contained status page.
+func renderHTML(writer io.Writer, r report.Report, minimal bool) error {
+	data := struct {
+		State     state.State
+		CurrentHP float32
+		Rows      []row
+		Minimal   bool
+	}{
+		State:   r.State,
+		Minimal: minimal,
+	}
+
+	if !minimal {
+		data.CurrentHP = r.CurrentHP
+		data.Rows = rows("", r)
+	}
+
+	return statusPage.Execute(writer, data)
+}
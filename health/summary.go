@@ -1,6 +1,8 @@
 package health
 
 import (
+	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/google/uuid"
@@ -16,6 +18,10 @@ type summary struct {
 	clock clockwork.Clock
 	mu    *sync.Mutex
 
+	// subscribersMu is held while broadcasting, so that slow subscribers only
+	// hold up other broadcasts rather than every reader of the summary.
+	subscribersMu *sync.Mutex
+
 	// health
 	totalHP float32
 	hp      float32
@@ -25,12 +31,12 @@ type summary struct {
 	checks           map[string]check.Check
 	lastResults      map[string]*check.Result
 	lastKnownResults map[string]*check.Result
+	panics           map[string]uint64
 	subscribers      map[string]chan check.Report
 }
 
 func (s *summary) update(report check.Report) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
 
 	// update internal data representation
 
@@ -47,33 +53,54 @@ func (s *summary) update(report check.Report) {
 	}
 
 	newResult := report.Result
+
+	panicErr := &check.PanicError{}
+	if errors.As(newResult.Error, &panicErr) {
+		s.panics[meta.Name]++
+	}
+
 	newScore := state.Score(newResult.State)
 	s.hp += newScore * float32(meta.Weight)
 	s.lastResults[meta.Name] = &newResult
 
 	// broadcast the report if the state for the dependency changed
 
+	broadcasts := make([]check.Report, 0, 2)
+
 	if lastResult == nil || lastResult.State != newResult.State {
-		s.broadcast(report)
+		broadcasts = append(broadcasts, report)
 	}
 
 	// update system state and broadcast if it changed
 
-	newState := state.ForScore(s.hp / s.totalHP)
+	s.system.CurrentHP = s.hp / s.totalHP
+
+	newState := state.ForScore(s.system.CurrentHP)
 	if newState != s.system.State {
 		s.system.State = newState
 		s.system.Timestamp = s.clock.Now()
-		s.system.CurrentHP = s.hp / s.totalHP
 
-		s.broadcast(check.Report{
+		broadcasts = append(broadcasts, check.Report{
 			Result: check.Result{
 				State:     s.system.State,
 				Timestamp: s.system.Timestamp,
 			},
 		})
 	}
+
+	// the summary is released before broadcasting, while holding on to the
+	// subscribers keeps the broadcasts in order
+	s.subscribersMu.Lock()
+	defer s.subscribersMu.Unlock()
+	s.mu.Unlock()
+
+	for _, r := range broadcasts {
+		s.broadcast(r)
+	}
 }
 
+// broadcast sends the report to every subscriber. The subscribers lock must be
+// held.
 func (s *summary) broadcast(report check.Report) {
 	for _, subscriber := range s.subscribers {
 		subscriber <- report
@@ -81,8 +108,8 @@ func (s *summary) broadcast(report check.Report) {
 }
 
 func (s *summary) subscribe() (chan check.Report, UnsubFunc) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.subscribersMu.Lock()
+	defer s.subscribersMu.Unlock()
 
 	uid := uuid.New().String()
 
@@ -92,15 +119,77 @@ func (s *summary) subscribe() (chan check.Report, UnsubFunc) {
 	s.subscribers[uid] = subscriber
 
 	return subscriber, func() {
-		s.mu.Lock()
-		defer s.mu.Unlock()
+		s.subscribersMu.Lock()
+		defer s.subscribersMu.Unlock()
 
 		delete(s.subscribers, uid)
 		close(subscriber)
 	}
 }
 
+// result returns the last result of the named check, or of the system when no
+// name is provided. Checks that have not reported are Unknown.
+func (s *summary) result(name string) (check.Result, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if name == "" {
+		return *(s.system), true
+	}
+
+	if _, ok := s.checks[name]; !ok {
+		return check.Result{}, false
+	}
+
+	if lastResult, ok := s.lastResults[name]; ok {
+		return *lastResult, true
+	}
+
+	return check.Result{State: state.Unknown}, true
+}
+
+// reportedHP returns the HP of the named checks (the lowest of their scores),
+// or of the system when no names are provided. Checks that have never reported
+// are excluded, and false is returned when none of them have.
+func (s *summary) reportedHP(names []string) (float32, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(names) == 0 {
+		hp, totalHP := float32(0), float32(0)
+		for name, lastResult := range s.lastResults {
+			weight := float32(s.checks[name].GetMetadata().Weight)
+			hp += state.Score(lastResult.State) * weight
+			totalHP += weight
+		}
+
+		if totalHP == 0 {
+			return 0, false, nil
+		}
+		return hp / totalHP, true, nil
+	}
+
+	hp, reported := float32(1), false
+	for _, name := range names {
+		if _, ok := s.checks[name]; !ok {
+			return 0, false, fmt.Errorf("%w: %s", ErrUnknownCheck, name)
+		}
+
+		if lastResult, ok := s.lastResults[name]; ok {
+			if score := state.Score(lastResult.State); score < hp {
+				hp = score
+			}
+			reported = true
+		}
+	}
+
+	return hp, reported, nil
+}
+
 func (s *summary) report() report.Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	results := make(map[string]report.CheckResult, len(s.checks))
 
 	for name, chk := range s.checks {
@@ -116,11 +205,19 @@ func (s *summary) report() report.Report {
 			lastKnownResult = lastResult
 		}
 
-		results[name] = report.CheckResult{
+		checkResult := report.CheckResult{
 			Metadata:       chk.GetMetadata(),
 			LastCheck:      *lastResult,
 			LastKnownCheck: *lastKnownResult,
+			Panics:         s.panics[name],
 		}
+
+		if reporter, ok := chk.(report.Reporter); ok {
+			nested := reporter.Report()
+			checkResult.Report = &nested
+		}
+
+		results[name] = checkResult
 	}
 
 	return report.Report{
@@ -54,10 +54,11 @@ func TestSummary(t *testing.T) {
 	chk := &staticCheck{}
 
 	s := &summary{
-		clock:   clock,
-		mu:      &sync.Mutex{},
-		totalHP: 100,
-		hp:      0,
+		clock:         clock,
+		mu:            &sync.Mutex{},
+		subscribersMu: &sync.Mutex{},
+		totalHP:       100,
+		hp:            0,
 		system: &check.Result{
 			State: state.Unknown,
 		},
@@ -66,6 +67,7 @@ func TestSummary(t *testing.T) {
 		},
 		lastResults:      make(map[string]*check.Result),
 		lastKnownResults: make(map[string]*check.Result),
+		panics:           make(map[string]uint64),
 		subscribers:      make(map[string]chan check.Report),
 	}
 
@@ -98,6 +100,47 @@ func TestSummary(t *testing.T) {
 	require.Equal(t, reportJSON, string(data))
 }
 
+func TestSummary_Panics(t *testing.T) {
+	chk := &staticCheck{}
+
+	s := &summary{
+		clock:         clockwork.NewFakeClock(),
+		mu:            &sync.Mutex{},
+		subscribersMu: &sync.Mutex{},
+		totalHP:       100,
+		system: &check.Result{
+			State: state.Unknown,
+		},
+		checks: map[string]check.Check{
+			chk.GetMetadata().Name: chk,
+		},
+		lastResults:      make(map[string]*check.Result),
+		lastKnownResults: make(map[string]*check.Result),
+		panics:           make(map[string]uint64),
+		subscribers:      make(map[string]chan check.Report),
+	}
+
+	for i := 0; i < 2; i++ {
+		s.update(check.Report{
+			Check: chk,
+			Result: check.Result{
+				State: state.Outage,
+				Error: check.WrapError(&check.PanicError{Value: "boom"}),
+			},
+		})
+	}
+
+	s.update(check.Report{
+		Check: chk,
+		Result: check.Result{
+			State: state.OK,
+			Error: check.WrapError(nil),
+		},
+	})
+
+	require.Equal(t, uint64(2), s.report().Results["static"].Panics)
+}
+
 var reportJSON = strings.TrimSpace(`
 {
   "state": "ok",
@@ -119,3 +162,83 @@ var reportJSON = strings.TrimSpace(`
   }
 }
 `)
+
+func TestSummary_CurrentHP(t *testing.T) {
+	database := &check.Stream{Metadata: check.Metadata{Name: "database", Weight: 1}}
+	cache := &check.Stream{Metadata: check.Metadata{Name: "cache", Weight: 1}}
+
+	s := &summary{
+		clock:         clockwork.NewFakeClock(),
+		mu:            &sync.Mutex{},
+		subscribersMu: &sync.Mutex{},
+		totalHP:       2,
+		system: &check.Result{
+			State: state.Unknown,
+		},
+		checks: map[string]check.Check{
+			"database": database,
+			"cache":    cache,
+		},
+		lastResults:      make(map[string]*check.Result),
+		lastKnownResults: make(map[string]*check.Result),
+		panics:           make(map[string]uint64),
+		subscribers:      make(map[string]chan check.Report),
+	}
+
+	s.update(check.Report{Check: database, Result: check.Result{State: state.Minor}})
+	s.update(check.Report{Check: cache, Result: check.Result{State: state.Minor}})
+	require.Equal(t, state.Minor, s.report().State)
+	require.Equal(t, float32(0.75), s.report().CurrentHP)
+
+	// the HP is updated even though the state of the system is unchanged
+	s.update(check.Report{Check: database, Result: check.Result{State: state.Major}})
+	require.Equal(t, state.Minor, s.report().State)
+	require.Equal(t, float32(0.625), s.report().CurrentHP)
+}
+
+func TestSummary_SlowSubscriber(t *testing.T) {
+	chk := &staticCheck{}
+
+	s := &summary{
+		clock:         clockwork.NewFakeClock(),
+		mu:            &sync.Mutex{},
+		subscribersMu: &sync.Mutex{},
+		totalHP:       100,
+		system: &check.Result{
+			State: state.Unknown,
+		},
+		checks: map[string]check.Check{
+			chk.GetMetadata().Name: chk,
+		},
+		lastResults:      make(map[string]*check.Result),
+		lastKnownResults: make(map[string]*check.Result),
+		panics:           make(map[string]uint64),
+		subscribers:      make(map[string]chan check.Report),
+	}
+
+	// the subscriber never reads, so the second update blocks once its buffer
+	// is full
+	reports, _ := s.subscribe()
+
+	go func() {
+		s.update(check.Report{Check: chk, Result: check.Result{State: state.Outage}})
+		s.update(check.Report{Check: chk, Result: check.Result{State: state.OK}})
+	}()
+
+	require.Eventually(t, func() bool {
+		return len(reports) == cap(reports)
+	}, time.Second, time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = s.report()
+		_, _ = s.result("")
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		require.Fail(t, "the report was blocked by a slow subscriber")
+	}
+}
@@ -0,0 +1,292 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/report"
+)
+
+const (
+	// MessageSubscribe adds the checks (names or patterns) to the selection of
+	// the connection.
+	MessageSubscribe = "subscribe"
+
+	// MessageUnsubscribe removes the checks from the selection of the connection.
+	MessageUnsubscribe = "unsubscribe"
+
+	// MessageSubscribed acknowledges a change in selection, listing the checks
+	// that are currently selected.
+	MessageSubscribed = "subscribed"
+
+	// MessageReport requests the current report, and is also used to reply.
+	MessageReport = "report"
+
+	// MessageEvent carries a change in the health of a selected check, or of
+	// the system when no check is provided.
+	MessageEvent = "event"
+
+	// MessagePing requests a MessagePong, for clients that cannot send control
+	// frames (e.g. browsers).
+	MessagePing = "ping"
+
+	// MessagePong replies to a MessagePing.
+	MessagePong = "pong"
+
+	// MessageError reports a problem with a message sent by the client.
+	MessageError = "error"
+)
+
+const (
+	webSocketPingInterval = 30 * time.Second
+	webSocketPongWait     = 60 * time.Second
+	webSocketWriteWait    = 10 * time.Second
+	webSocketBuffer       = 64
+)
+
+// WebSocketMessage is the JSON message exchanged over the WebSocket connection
+// served by WebSocketHandler.
+type WebSocketMessage struct {
+	Type   string         `json:"type"`
+	Checks []string       `json:"checks,omitempty"`
+	Check  string         `json:"check,omitempty"`
+	Result *check.Result  `json:"result,omitempty"`
+	Report *report.Report `json:"report,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// WebSocketHandler returns an http.HandlerFunc that streams changes in health
+// over a WebSocket connection. Clients select the checks they're interested in
+// by sending "subscribe" and "unsubscribe" messages listing check names or
+// patterns (as supported by path.Match), and receive an "event" message each
+// time the state of a selected check changes. Changes to the system state are
+// always sent. Clients can request the current report at any time using a
+// "report" message. The connection is kept alive using ping and pong control
+// frames, and closed when the client falls too far behind.
+//
+// When authentication is configured, unauthenticated callers are rejected. When
+// the handler is minimal, only the state of the system is sent.
+func WebSocketHandler(monitor *Monitor, opts ...HandlerOption) http.HandlerFunc {
+	options := newHandlerOptions(opts)
+	upgrader := &websocket.Upgrader{}
+
+	return func(writer http.ResponseWriter, request *http.Request) {
+		if !options.authenticated(request) {
+			options.unauthorized(writer)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(writer, request, nil)
+		if err != nil {
+			// the upgrader has already responded to the client
+			return
+		}
+		defer conn.Close()
+
+		ctx, cancel := context.WithCancel(request.Context())
+		defer cancel()
+
+		session := &webSocketSession{
+			monitor: monitor,
+			options: options,
+			request: request,
+			cancel:  cancel,
+			writes:  make(chan WebSocketMessage, webSocketBuffer),
+			mu:      &sync.Mutex{},
+		}
+
+		reports, unsubscribe := monitor.Subscribe()
+		defer unsubscribe()
+
+		go session.forward(ctx, reports)
+		go session.write(ctx, conn)
+
+		session.read(conn)
+	}
+}
+
+type webSocketSession struct {
+	monitor *Monitor
+	options *handlerOptions
+	request *http.Request
+	cancel  context.CancelFunc
+	writes  chan WebSocketMessage
+
+	mu       *sync.Mutex
+	selected []string
+}
+
+// send queues a message for the client. Clients that fall too far behind are
+// disconnected so that they never block the Monitor.
+func (s *webSocketSession) send(message WebSocketMessage) {
+	select {
+	case s.writes <- message:
+	default:
+		s.cancel()
+	}
+}
+
+// read handles messages from the client until the connection is closed.
+func (s *webSocketSession) read(conn *websocket.Conn) {
+	defer s.cancel()
+
+	extend := func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(webSocketPongWait))
+	}
+
+	_ = extend("")
+	conn.SetPongHandler(extend)
+
+	for {
+		message := WebSocketMessage{}
+		if err := conn.ReadJSON(&message); err != nil {
+			if !isJSONError(err) {
+				return
+			}
+
+			s.send(WebSocketMessage{Type: MessageError, Error: err.Error()})
+			continue
+		}
+
+		_ = extend("")
+
+		switch message.Type {
+		case MessageSubscribe:
+			s.send(WebSocketMessage{Type: MessageSubscribed, Checks: s.subscribe(message.Checks)})
+		case MessageUnsubscribe:
+			s.send(WebSocketMessage{Type: MessageSubscribed, Checks: s.unsubscribe(message.Checks)})
+		case MessageReport:
+			r, minimal := s.options.view(s.monitor, s.request)
+			if minimal {
+				r = report.Report{Result: check.Result{State: r.State}}
+			}
+			s.send(WebSocketMessage{Type: MessageReport, Report: &r})
+		case MessagePing:
+			s.send(WebSocketMessage{Type: MessagePong})
+		default:
+			s.send(WebSocketMessage{Type: MessageError, Error: "unknown message type: " + message.Type})
+		}
+	}
+}
+
+// write sends queued messages and periodic pings to the client.
+func (s *webSocketSession) write(ctx context.Context, conn *websocket.Conn) {
+	ticker := time.NewTicker(webSocketPingInterval)
+	defer ticker.Stop()
+
+	// closing the connection unblocks the reader
+	defer conn.Close()
+
+	for {
+		select {
+		case message := <-s.writes:
+			_ = conn.SetWriteDeadline(time.Now().Add(webSocketWriteWait))
+			if err := conn.WriteJSON(message); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(webSocketWriteWait)); err != nil {
+				return
+			}
+		case <-ctx.Done():
+			_ = conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+				time.Now().Add(webSocketWriteWait))
+			return
+		}
+	}
+}
+
+// forward relays reports for the system and the selected checks. It drains
+// the subscription until it's closed so that the Monitor is never blocked.
+func (s *webSocketSession) forward(ctx context.Context, reports chan check.Report) {
+	for r := range reports {
+		if ctx.Err() != nil {
+			continue
+		}
+
+		message := WebSocketMessage{Type: MessageEvent}
+
+		if r.Check != nil {
+			name := r.Check.GetMetadata().Name
+			if s.options.minimal || !s.matches(name) {
+				continue
+			}
+			message.Check = name
+		}
+
+		result := r.Result
+		if r.Check == nil && s.options.minimal {
+			result = check.Result{State: result.State}
+		} else if s.options.redact != nil {
+			result = redactResult(result, s.options.redact)
+		}
+		message.Result = &result
+
+		s.send(message)
+	}
+}
+
+func (s *webSocketSession) subscribe(checks []string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, name := range checks {
+		if !contains(s.selected, name) {
+			s.selected = append(s.selected, name)
+		}
+	}
+
+	return append([]string{}, s.selected...)
+}
+
+func (s *webSocketSession) unsubscribe(checks []string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	remaining := s.selected[:0]
+	for _, name := range s.selected {
+		if !contains(checks, name) {
+			remaining = append(remaining, name)
+		}
+	}
+	s.selected = remaining
+
+	return append([]string{}, s.selected...)
+}
+
+func (s *webSocketSession) matches(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, pattern := range s.selected {
+		if ok, _ := path.Match(pattern, name); ok || pattern == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isJSONError reports whether a message was read, but could not be decoded.
+func isJSONError(err error) bool {
+	syntaxErr := &json.SyntaxError{}
+	typeErr := &json.UnmarshalTypeError{}
+	return errors.As(err, &syntaxErr) || errors.As(err, &typeErr)
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,178 @@
+package health_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/mjpitz/go-gracefully/check"
+	"github.com/mjpitz/go-gracefully/health"
+	"github.com/mjpitz/go-gracefully/state"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebSocketHandler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	monitor, states := newControlledMonitor(ctx, t)
+
+	server := httptest.NewServer(health.WebSocketHandler(monitor))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	exchange := func(request health.WebSocketMessage) health.WebSocketMessage {
+		require.NoError(t, conn.WriteJSON(request))
+
+		response := health.WebSocketMessage{}
+		require.NoError(t, conn.ReadJSON(&response))
+		return response
+	}
+
+	receive := func() health.WebSocketMessage {
+		message := health.WebSocketMessage{}
+		require.NoError(t, conn.ReadJSON(&message))
+		return message
+	}
+
+	{
+		response := exchange(health.WebSocketMessage{Type: health.MessageSubscribe, Checks: []string{"contr*"}})
+		require.Equal(t, health.MessageSubscribed, response.Type)
+		require.Equal(t, []string{"contr*"}, response.Checks)
+	}
+
+	states <- state.Major
+
+	{
+		event := receive()
+		require.Equal(t, health.MessageEvent, event.Type)
+		require.Equal(t, "controlled", event.Check)
+		require.Equal(t, state.Major, event.Result.State)
+	}
+
+	{
+		event := receive()
+		require.Equal(t, health.MessageEvent, event.Type)
+		require.Empty(t, event.Check)
+		require.Equal(t, state.Major, event.Result.State)
+	}
+
+	{
+		response := exchange(health.WebSocketMessage{Type: health.MessageReport})
+		require.Equal(t, health.MessageReport, response.Type)
+		require.Equal(t, state.Major, response.Report.State)
+		require.Equal(t, state.Major, response.Report.Results["controlled"].LastCheck.State)
+	}
+
+	require.Equal(t, health.MessagePong, exchange(health.WebSocketMessage{Type: health.MessagePing}).Type)
+	require.Equal(t, health.MessageError, exchange(health.WebSocketMessage{Type: "unknown"}).Type)
+
+	{
+		response := exchange(health.WebSocketMessage{Type: health.MessageUnsubscribe, Checks: []string{"contr*"}})
+		require.Equal(t, health.MessageSubscribed, response.Type)
+		require.Empty(t, response.Checks)
+	}
+
+	states <- state.OK
+
+	// only the system event is received once unsubscribed
+	{
+		event := receive()
+		require.Empty(t, event.Check)
+		require.Equal(t, state.OK, event.Result.State)
+	}
+}
+
+func TestWebSocketHandler_Unauthenticated(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	monitor, _ := newControlledMonitor(ctx, t)
+
+	server := httptest.NewServer(health.WebSocketHandler(monitor, health.WithBearerToken("secret")))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.Error(t, err)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{"Authorization": {"Bearer secret"}})
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+}
+
+func TestWebSocketHandler_Redaction(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	results := make(chan check.Result)
+
+	monitor := health.NewMonitor(&check.Stream{
+		Metadata: check.Metadata{
+			Name:   "database",
+			Weight: 10,
+		},
+		WatchFunc: func(ctx context.Context, channel chan check.Result) {
+			for {
+				select {
+				case result := <-results:
+					channel <- result
+				case <-ctx.Done():
+					return
+				}
+			}
+		},
+	})
+	require.NoError(t, monitor.Start(ctx))
+
+	server := httptest.NewServer(health.WebSocketHandler(monitor,
+		health.WithRedactor(health.RedactPatterns(regexp.MustCompile(`hunter2`)))))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(health.WebSocketMessage{Type: health.MessageSubscribe, Checks: []string{"*"}}))
+
+	message := health.WebSocketMessage{}
+	require.NoError(t, conn.ReadJSON(&message))
+	require.Equal(t, health.MessageSubscribed, message.Type)
+
+	// system events are interleaved with those of the check
+	receiveCheck := func() health.WebSocketMessage {
+		for {
+			message := health.WebSocketMessage{}
+			require.NoError(t, conn.ReadJSON(&message))
+			if message.Check != "" {
+				return message
+			}
+		}
+	}
+
+	// a healthy result may carry a nil *check.Error
+	results <- check.Result{State: state.OK, Error: check.WrapError(nil)}
+
+	message = receiveCheck()
+	require.Equal(t, "database", message.Check)
+	require.Equal(t, state.OK, message.Result.State)
+	require.True(t, message.Result.Error == nil)
+
+	results <- check.Result{State: state.Outage, Error: check.WrapError(fmt.Errorf("password hunter2 rejected"))}
+
+	message = receiveCheck()
+	require.Equal(t, "database", message.Check)
+	require.Equal(t, "password [REDACTED] rejected", message.Result.Error.Error())
+}
@@ -1,14 +1,25 @@
 package report
 
 import (
+	"encoding/json"
+
 	"github.com/mjpitz/go-gracefully/check"
 )
 
-// CheckResult is a static capture of a check and associated results.
+// Reporter is implemented by checks that produce a report of their own, such
+// as a nested Monitor.
+type Reporter interface {
+	Report() Report
+}
+
+// CheckResult is a static capture of a check and associated results. When the
+// check is a Reporter, its report is nested within the result.
 type CheckResult struct {
 	check.Metadata
 	LastCheck      check.Result `json:"last_check"`
 	LastKnownCheck check.Result `json:"last_known_check"`
+	Panics         uint64       `json:"panics,omitempty"`
+	Report         *Report      `json:"report,omitempty"`
 }
 
 // Report is a static capture of an application and associated results.
@@ -16,3 +27,24 @@ type Report struct {
 	check.Result
 	Results map[string]CheckResult `json:"results"`
 }
+
+// UnmarshalJSON enables JSON deserialization of a report. It's required since
+// the embedded check.Result provides its own implementation.
+func (r *Report) UnmarshalJSON(bytes []byte) error {
+	if err := json.Unmarshal(bytes, &r.Result); err != nil {
+		return err
+	}
+
+	aux := struct {
+		Results map[string]CheckResult `json:"results"`
+	}{}
+
+	if err := json.Unmarshal(bytes, &aux); err != nil {
+		return err
+	}
+
+	r.Results = aux.Results
+	return nil
+}
+
+var _ json.Unmarshaler = &Report{}
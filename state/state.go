@@ -48,3 +48,16 @@ func ForScore(score float32) State {
 	}
 	return OK
 }
+
+// Worst returns the least healthy of the provided states, as determined by
+// their Score. Since it has the lowest score, Unknown is considered worse than
+// an Outage. OK is returned when no states are provided.
+func Worst(states ...State) State {
+	worst := OK
+	for _, state := range states {
+		if Score(state) < Score(worst) {
+			worst = state
+		}
+	}
+	return worst
+}
@@ -37,3 +37,10 @@ func TestIdentity(t *testing.T) {
 	require.Equal(t, state.Minor, state.ForScore(state.Score(state.Minor)))
 	require.Equal(t, state.OK, state.ForScore(state.Score(state.OK)))
 }
+
+func TestWorst(t *testing.T) {
+	require.Equal(t, state.OK, state.Worst())
+	require.Equal(t, state.Minor, state.Worst(state.OK, state.Minor))
+	require.Equal(t, state.Outage, state.Worst(state.Major, state.Outage, state.Minor))
+	require.Equal(t, state.Unknown, state.Worst(state.Outage, state.Unknown))
+}